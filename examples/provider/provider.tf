@@ -1,3 +1,15 @@
 provider "evm" {
   node_url = "https://ethereum-sepolia.publicnode.com"
-}
\ No newline at end of file
+
+  // Optional named signers referenced by resources with `signer_name`
+  signers {
+    name = "deployer"
+    env  = "EVM_DEPLOYER_PK"
+  }
+
+  // Optional retry policy for transient node errors
+  retry {
+    max_attempts = 5
+    backoff      = "1s"
+  }
+}
@@ -0,0 +1,3 @@
+# Contract is imported by address, artifact and constructor args are stored from the configuration
+# on the next apply without deploying the contract again
+terraform import evm_contract.test_token 0x5FbDB2315678afecb367f032d93F642f64180aa3
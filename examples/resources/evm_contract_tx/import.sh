@@ -0,0 +1,2 @@
+# Transaction is imported by hash, it is not sent again as long as the configuration describes the same call
+terraform import evm_contract_tx.token_transfer 0x9c0f5c0e3ab4e0f1d9bd3a1f1f4a0e4c8e8d3d2c7a3b1e0f6d5c4b3a29180706
@@ -0,0 +1,11 @@
+resource "evm_create2_factory" "factory" {
+  signer = var.treasury_pk
+}
+
+resource "evm_contract" "token" {
+  artifact_path    = "./artifacts/Token.json"
+  signer           = var.deployer_pk
+  constructor_args = ["Token", "TKN", 1000000 * pow(10, 18), 18]
+  salt             = "0x01"
+  create2_factory  = evm_create2_factory.factory.address
+}
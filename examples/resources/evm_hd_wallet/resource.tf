@@ -0,0 +1,8 @@
+resource "evm_hd_wallet" "environment" {
+  // Mnemonic is generated when not specified
+  account_count = 3
+}
+
+output "deployer_address" {
+  value = evm_hd_wallet.environment.accounts[0].address
+}
@@ -0,0 +1,13 @@
+resource "evm_proxy" "bridge" {
+  artifact_path       = "./artifacts/Bridge.json"
+  proxy_artifact_path = "./artifacts/TransparentUpgradeableProxy.json"
+  kind                = "transparent"
+  admin               = var.admin_address
+  initializer         = "initialize(address,uint256)"
+  initializer_args    = [var.owner_address, 1]
+  signer              = var.deployer_pk
+}
+
+output "bridge_address" {
+  value = evm_proxy.bridge.address
+}
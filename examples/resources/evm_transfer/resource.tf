@@ -0,0 +1,9 @@
+resource "evm_transfer" "fund_deployer" {
+  signer = var.treasury_pk
+  to     = evm_random_pk.deployer.address
+  value  = "0.1 ether"
+}
+
+output "funding_fee_wei" {
+  value = evm_transfer.fund_deployer.fee_paid_wei
+}
@@ -0,0 +1,155 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"terraform-provider-evm/internal/utils"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// contractArtifact is the compiled contract given by the artifact content or path.
+type contractArtifact struct {
+	*utils.Artifact
+	// hash is hex encoded sha256 of the artifact content
+	hash string
+	// bytecodeHash is keccak256 of the bytecode without compiler metadata
+	bytecodeHash string
+	abi          abi.ABI
+	abiJson      string
+	signatures   []string
+}
+
+func newContractArtifact(artifactJson string, format utils.ArtifactFormat, contract string) (*contractArtifact, error) {
+	artifact, err := utils.ParseArtifact(artifactJson, format, contract)
+	if err != nil {
+		return nil, err
+	}
+	parsedABI, err := abi.JSON(strings.NewReader(artifact.Abi))
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse abi: %w", err)
+	}
+	var compactAbi bytes.Buffer
+	if err := json.Compact(&compactAbi, []byte(artifact.Abi)); err != nil {
+		return nil, fmt.Errorf("cannot parse abi: %w", err)
+	}
+
+	hash := sha256.Sum256([]byte(artifactJson))
+	return &contractArtifact{
+		Artifact:     artifact,
+		hash:         hex.EncodeToString(hash[:]),
+		bytecodeHash: crypto.Keccak256Hash(utils.MaskCode(artifact.Bytecode, nil)).Hex(),
+		abi:          parsedABI,
+		abiJson:      compactAbi.String(),
+		signatures:   abiSignatures(parsedABI),
+	}, nil
+}
+
+// loadArtifact loads the artifact given by the content or the path, returning nil when neither is known. Contract
+// selects one of the contracts of the combined output.
+func loadArtifact(content types.String, artifactPath types.String, format types.String, contract types.String, diags *diag.Diagnostics) *contractArtifact {
+	var artifactJson string
+	var attrPath path.Path
+	switch {
+	case content.IsUnknown() || artifactPath.IsUnknown() || format.IsUnknown() || contract.IsUnknown():
+		return nil
+	case !artifactPath.IsNull():
+		attrPath = path.Root("artifact_path")
+		data, err := os.ReadFile(artifactPath.ValueString())
+		if err != nil {
+			diags.AddAttributeError(attrPath, "Cannot read artifact", err.Error())
+			return nil
+		}
+		artifactJson = string(data)
+	case !content.IsNull():
+		attrPath = path.Root("artifact")
+		artifactJson = content.ValueString()
+	default:
+		return nil
+	}
+
+	artifactFormat, err := utils.ParseArtifactFormat(format.ValueString())
+	if err != nil {
+		diags.AddAttributeError(path.Root("artifact_format"), "Invalid artifact format", err.Error())
+		return nil
+	}
+	artifact, err := newContractArtifact(artifactJson, artifactFormat, contract.ValueString())
+	if err != nil {
+		diags.AddAttributeError(attrPath, "Invalid artifact", err.Error())
+		return nil
+	}
+	return artifact
+}
+
+// abiSignatures lists sorted signatures of the constructor, functions, events and errors declared in the ABI.
+func abiSignatures(contractAbi abi.ABI) []string {
+	var signatures []string
+	if contractAbi.Constructor.Type == abi.Constructor {
+		signatures = append(signatures, contractAbi.Constructor.String())
+	}
+	for _, method := range contractAbi.Methods {
+		signatures = append(signatures, method.String())
+	}
+	for _, event := range contractAbi.Events {
+		signatures = append(signatures, event.String())
+	}
+	for _, abiError := range contractAbi.Errors {
+		signatures = append(signatures, abiError.String())
+	}
+	if contractAbi.HasFallback() {
+		signatures = append(signatures, contractAbi.Fallback.String())
+	}
+	if contractAbi.HasReceive() {
+		signatures = append(signatures, contractAbi.Receive.String())
+	}
+	sort.Strings(signatures)
+	return signatures
+}
+
+// parseLibraries parses addresses of the libraries by the fully qualified name, checking that the libraries
+// are exactly the ones referenced by the artifact. Unknown addresses are skipped.
+func parseLibraries(ctx context.Context, libraries types.Map, artifact *contractArtifact, diags *diag.Diagnostics) map[string]common.Address {
+	if libraries.IsUnknown() {
+		return nil
+	}
+	elements := map[string]types.String{}
+	diags.Append(libraries.ElementsAs(ctx, &elements, false)...)
+
+	addresses := map[string]common.Address{}
+	names := make([]string, 0, len(elements))
+	for name, address := range elements {
+		names = append(names, name)
+		if address.IsUnknown() {
+			continue
+		}
+		if !common.IsHexAddress(address.ValueString()) {
+			diags.AddAttributeError(
+				path.Root("libraries").AtMapKey(name),
+				"Invalid library address",
+				fmt.Sprintf("Expected address of library %s, got '%s'", name, address.ValueString()),
+			)
+			continue
+		}
+		addresses[name] = common.HexToAddress(address.ValueString())
+	}
+
+	if artifact != nil {
+		if err := artifact.CheckLibraries(names); err != nil {
+			diags.AddAttributeError(path.Root("libraries"), "Invalid libraries", err.Error())
+		}
+	}
+	return addresses
+}
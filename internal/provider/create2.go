@@ -0,0 +1,61 @@
+package provider
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// defaultCreate2Factory is the deterministic deployment proxy available at the same address on most
+// chains, see https://github.com/Arachnid/deterministic-deployment-proxy. The factory deploys the init
+// code following the 32-byte salt in the calldata with CREATE2 and returns the address of the contract.
+var defaultCreate2Factory = common.HexToAddress("0x4e59b44847b379578588920cA78FbF26c0B4956C")
+
+// parseSalt parses 32-byte hex salt, shorter values are left padded with zeros. The salt is not
+// known when it's null or unknown.
+func parseSalt(salt types.String, diags *diag.Diagnostics) (common.Hash, bool) {
+	if salt.IsNull() || salt.IsUnknown() {
+		return common.Hash{}, false
+	}
+	value, err := hexutil.Decode(salt.ValueString())
+	if err == nil && len(value) > common.HashLength {
+		err = fmt.Errorf("salt is longer than %d bytes", common.HashLength)
+	}
+	if err != nil {
+		diags.AddAttributeError(path.Root("salt"), "Invalid salt", fmt.Sprintf("Expected up to 32-byte hex with 0x prefix, got '%s': %v", salt.ValueString(), err))
+		return common.Hash{}, false
+	}
+	return common.BytesToHash(value), true
+}
+
+// parseCreate2Factory parses address of the CREATE2 factory, which defaults to the deterministic
+// deployment proxy.
+func parseCreate2Factory(factory types.String, diags *diag.Diagnostics) common.Address {
+	if factory.IsNull() || factory.IsUnknown() {
+		return defaultCreate2Factory
+	}
+	if !common.IsHexAddress(factory.ValueString()) {
+		diags.AddAttributeError(path.Root("create2_factory"), "Invalid CREATE2 factory", fmt.Sprintf("Expected address, got '%s'", factory.ValueString()))
+		return common.Address{}
+	}
+	return common.HexToAddress(factory.ValueString())
+}
+
+// create2Address returns address of the contract deployed by the factory with CREATE2.
+func create2Address(factory common.Address, salt common.Hash, initCode []byte) common.Address {
+	return crypto.CreateAddress2(factory, salt, crypto.Keccak256(initCode))
+}
+
+// create2FactoryDeployer is the one-time account deploying the deterministic deployment proxy with
+// create2FactoryDeployment. Nobody knows its key, as the signature of the transaction is made up.
+var create2FactoryDeployer = common.HexToAddress("0x3fab184622dc19b6109349b94811493bf2a45362")
+
+// create2FactoryDeployment is the presigned transaction deploying the deterministic deployment proxy
+// with gas price of 100 gwei and gas limit of 100000. It's signed without chain ID (pre-EIP-155), so the
+// same transaction deploys the proxy at the same address on every chain.
+var create2FactoryDeployment = hexutil.MustDecode("0xf8a58085174876e800830186a08080b853604580600e600039806000f350fe7fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffe03601600081602082378035828234f58015156039578182fd5b8082525050506014600cf31ba02222222222222222222222222222222222222222222222222222222222222222a02222222222222222222222222222222222222222222222222222222222222222")
@@ -0,0 +1,52 @@
+package provider
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreate2Address(t *testing.T) {
+	// Examples of EIP-1014
+	assert.Equal(t, common.HexToAddress("0x4D1A2e2bB4F88F0250f26Ffff098B0b30B26BF38"),
+		create2Address(common.Address{}, common.Hash{}, []byte{0x00}))
+	assert.Equal(t, common.HexToAddress("0xB928f69Bb1D91Cd65274e3c79d8986362984fDA3"),
+		create2Address(common.HexToAddress("0xdeadbeef00000000000000000000000000000000"), common.Hash{}, []byte{0x00}))
+}
+
+func TestParseSalt(t *testing.T) {
+	var diags diag.Diagnostics
+	salt, ok := parseSalt(types.StringValue("0x01"), &diags)
+	assert.True(t, ok)
+	assert.Equal(t, common.BigToHash(common.Big1), salt)
+
+	_, ok = parseSalt(types.StringNull(), &diags)
+	assert.False(t, ok)
+	assert.False(t, diags.HasError())
+
+	_, ok = parseSalt(types.StringValue("0x"+common.Hash{}.Hex()[2:]+"00"), &diags)
+	assert.False(t, ok)
+	assert.True(t, diags.HasError())
+
+	assert.Equal(t, defaultCreate2Factory, parseCreate2Factory(types.StringNull(), &diags))
+}
+
+func TestCreate2FactoryDeployment(t *testing.T) {
+	tx := new(ethTypes.Transaction)
+	if err := tx.UnmarshalBinary(create2FactoryDeployment); err != nil {
+		t.Fatalf("Cannot decode deployment: %v", err)
+	}
+	assert.False(t, tx.Protected())
+	sender, err := ethTypes.Sender(ethTypes.HomesteadSigner{}, tx)
+	assert.NoError(t, err)
+	assert.Equal(t, create2FactoryDeployer, sender)
+	assert.Equal(t, defaultCreate2Factory, crypto.CreateAddress(sender, tx.Nonce()))
+	assert.Equal(t, big.NewInt(100000*params.GWei*100), tx.Cost())
+}
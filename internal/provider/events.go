@@ -0,0 +1,190 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"terraform-provider-evm/internal/utils"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// eventAttributes adds the attributes exposing events emitted by the transaction to the resource schema.
+func eventAttributes(attributes map[string]schema.Attribute) map[string]schema.Attribute {
+	attributes["events"] = schema.ListNestedAttribute{
+		MarkdownDescription: "Events emitted by the transaction, decoded with the contract ABI. Logs of events which are not declared in the ABI are skipped",
+		Computed:            true,
+		NestedObject: schema.NestedAttributeObject{
+			Attributes: map[string]schema.Attribute{
+				"name": schema.StringAttribute{
+					MarkdownDescription: "Event name",
+					Computed:            true,
+				},
+				"address": schema.StringAttribute{
+					MarkdownDescription: "Address of the contract emitting the event",
+					Computed:            true,
+				},
+				"log_index": schema.Int64Attribute{
+					MarkdownDescription: "Index of the log in the block",
+					Computed:            true,
+				},
+				"args": schema.MapAttribute{
+					MarkdownDescription: "Event arguments formatted as strings, unnamed arguments are named by position (e.g. `arg0`). Indexed arguments of dynamic types (e.g. `string`) are hashes of the values",
+					ElementType:         types.StringType,
+					Computed:            true,
+				},
+			},
+		},
+	}
+	attributes["capture"] = schema.MapAttribute{
+		MarkdownDescription: "Map of names to event arguments in `Event.argument` format (e.g. `PoolCreated.pool`) to capture into `captured`. Argument of the first matching event is captured, the transaction fails if it doesn't emit the event",
+		ElementType:         types.StringType,
+		Optional:            true,
+	}
+	attributes["captured"] = schema.MapAttribute{
+		MarkdownDescription: "Event arguments captured according to `capture`",
+		ElementType:         types.StringType,
+		Computed:            true,
+	}
+	return attributes
+}
+
+// eventModel describes an element of the events attribute.
+type eventModel struct {
+	Name     types.String `tfsdk:"name"`
+	Address  types.String `tfsdk:"address"`
+	LogIndex types.Int64  `tfsdk:"log_index"`
+	Args     types.Map    `tfsdk:"args"`
+}
+
+var eventType = types.ObjectType{AttrTypes: map[string]attr.Type{
+	"name":      types.StringType,
+	"address":   types.StringType,
+	"log_index": types.Int64Type,
+	"args":      types.MapType{ElemType: types.StringType},
+}}
+
+// eventArg refers to an argument of the event by name.
+type eventArg struct {
+	event string
+	arg   string
+}
+
+// parseCapture parses capture attribute, checking that the events and their arguments are declared
+// in the ABIs if any is given.
+func parseCapture(ctx context.Context, capture types.Map, abis []*abi.ABI, diags *diag.Diagnostics) map[string]eventArg {
+	if capture.IsNull() || capture.IsUnknown() {
+		return nil
+	}
+
+	var specs map[string]types.String
+	diags.Append(capture.ElementsAs(ctx, &specs, true)...)
+	if diags.HasError() {
+		return nil
+	}
+
+	args := make(map[string]eventArg, len(specs))
+	for name, spec := range specs {
+		if spec.IsUnknown() {
+			continue
+		}
+		attrPath := path.Root("capture").AtMapKey(name)
+		event, arg, ok := strings.Cut(spec.ValueString(), ".")
+		if !ok || event == "" || arg == "" {
+			diags.AddAttributeError(attrPath, "Invalid capture", fmt.Sprintf("Expected event argument in `Event.argument` format, got '%s'", spec.ValueString()))
+			continue
+		}
+		if len(abis) > 0 && !isEventArgDeclared(abis, event, arg) {
+			diags.AddAttributeError(attrPath, "Invalid capture", fmt.Sprintf("Event %s with argument %s is not declared in the ABI", event, arg))
+			continue
+		}
+		args[name] = eventArg{event, arg}
+	}
+	return args
+}
+
+func isEventArgDeclared(abis []*abi.ABI, event string, arg string) bool {
+	for _, contractAbi := range abis {
+		for _, abiEvent := range contractAbi.Events {
+			if abiEvent.Name != event {
+				continue
+			}
+			for i, input := range abiEvent.Inputs {
+				if input.Name == arg || (input.Name == "" && arg == fmt.Sprintf("arg%d", i)) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// decodeEvents decodes logs of the receipt with the ABIs into the events attribute and captures event
+// arguments.
+func decodeEvents(ctx context.Context, receipt *ethTypes.Receipt, abis []*abi.ABI, capture map[string]eventArg,
+	diags *diag.Diagnostics) (types.List, types.Map) {
+	events := []eventModel{}
+	for _, log := range receipt.Logs {
+		name, args, err := utils.DecodeEvent(log, abis...)
+		if errors.Is(err, utils.ErrEventNotFound) {
+			continue
+		}
+		if err != nil {
+			diags.AddWarning("Cannot decode event", fmt.Sprintf("Log %d of transaction %s: %v", log.Index, receipt.TxHash.Hex(), err))
+			continue
+		}
+
+		argsValue, argsDiags := types.MapValueFrom(ctx, types.StringType, args)
+		diags.Append(argsDiags...)
+		events = append(events, eventModel{
+			Name:     types.StringValue(name),
+			Address:  types.StringValue(log.Address.Hex()),
+			LogIndex: types.Int64Value(int64(log.Index)),
+			Args:     argsValue,
+		})
+	}
+
+	eventsValue, eventsDiags := types.ListValueFrom(ctx, eventType, events)
+	diags.Append(eventsDiags...)
+	return eventsValue, captureEvents(ctx, receipt.TxHash.Hex(), eventsValue, capture, diags)
+}
+
+// captureEvents captures arguments of the first matching events of the transaction.
+func captureEvents(ctx context.Context, txHash string, events types.List, capture map[string]eventArg, diags *diag.Diagnostics) types.Map {
+	var eventModels []eventModel
+	if !events.IsNull() && !events.IsUnknown() {
+		diags.Append(events.ElementsAs(ctx, &eventModels, false)...)
+	}
+
+	captured := map[string]string{}
+	for key, eventArg := range capture {
+		for _, event := range eventModels {
+			if event.Name.ValueString() != eventArg.event {
+				continue
+			}
+			if value, ok := event.Args.Elements()[eventArg.arg].(types.String); ok {
+				captured[key] = value.ValueString()
+				break
+			}
+		}
+		if _, ok := captured[key]; !ok {
+			diags.AddAttributeError(
+				path.Root("capture").AtMapKey(key),
+				"Event not captured",
+				fmt.Sprintf("Transaction %s didn't emit event %s with argument %s", txHash, eventArg.event, eventArg.arg),
+			)
+		}
+	}
+
+	capturedValue, capturedDiags := types.MapValueFrom(ctx, types.StringType, captured)
+	diags.Append(capturedDiags...)
+	return capturedValue
+}
@@ -0,0 +1,171 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"terraform-provider-evm/internal/utils"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Descriptions of the fee attributes shared by resource and provider schemas.
+const (
+	gasLimitDescription             = "Gas limit of the transaction, estimated by the node when not specified. Conflicts with `gas_limit_multiplier`"
+	gasLimitMultiplierDescription   = "Multiplier (at least 1) applied to the estimated gas limit, e.g. `1.2` to add 20% margin when estimation depends on the state. Conflicts with `gas_limit`"
+	maxFeePerGasDescription         = "Maximum total fee per gas of EIP-1559 transaction, in wei or with a unit suffix (e.g. `30 gwei`). Conflicts with `gas_price`"
+	maxPriorityFeePerGasDescription = "Maximum priority fee per gas of EIP-1559 transaction, in wei or with a unit suffix (e.g. `2 gwei`). Conflicts with `gas_price`"
+	gasPriceDescription             = "Gas price of legacy (pre EIP-1559) transaction, in wei or with a unit suffix (e.g. `20 gwei`). Conflicts with `max_fee_per_gas` and `max_priority_fee_per_gas`"
+	feeDefaultsDescription          = ". Resources specifying any of the gas limit or the fee attributes don't use provider defaults for that group"
+)
+
+// feeAttributes adds the attributes controlling transaction gas limit and fees to the resource schema.
+func feeAttributes(attributes map[string]schema.Attribute) map[string]schema.Attribute {
+	attributes["gas_limit"] = schema.Int64Attribute{
+		MarkdownDescription: gasLimitDescription,
+		Optional:            true,
+	}
+	attributes["gas_limit_multiplier"] = schema.Float64Attribute{
+		MarkdownDescription: gasLimitMultiplierDescription,
+		Optional:            true,
+	}
+	attributes["max_fee_per_gas"] = schema.StringAttribute{
+		MarkdownDescription: maxFeePerGasDescription,
+		Optional:            true,
+	}
+	attributes["max_priority_fee_per_gas"] = schema.StringAttribute{
+		MarkdownDescription: maxPriorityFeePerGasDescription,
+		Optional:            true,
+	}
+	attributes["gas_price"] = schema.StringAttribute{
+		MarkdownDescription: gasPriceDescription,
+		Optional:            true,
+	}
+	return attributes
+}
+
+// feeConfig holds gas limit and fee attributes shared by resource and provider models.
+type feeConfig struct {
+	GasLimit             types.Int64
+	GasLimitMultiplier   types.Float64
+	MaxFeePerGas         types.String
+	MaxPriorityFeePerGas types.String
+	GasPrice             types.String
+}
+
+// txFees are resolved gas limit and fees applied to transaction options, nil or zero values are
+// left for bind to estimate.
+type txFees struct {
+	gasLimit           uint64
+	gasLimitMultiplier float64
+	gasPrice           *big.Int
+	gasFeeCap          *big.Int
+	gasTipCap          *big.Int
+}
+
+// validate checks values of the known attributes and that they don't conflict with each other.
+func (c feeConfig) validate(diags *diag.Diagnostics) {
+	if !c.GasLimit.IsNull() && !c.GasLimit.IsUnknown() && c.GasLimit.ValueInt64() <= 0 {
+		diags.AddAttributeError(path.Root("gas_limit"), "Invalid gas limit", "Gas limit should be positive")
+	}
+	if !c.GasLimitMultiplier.IsNull() && !c.GasLimitMultiplier.IsUnknown() && c.GasLimitMultiplier.ValueFloat64() < 1 {
+		diags.AddAttributeError(path.Root("gas_limit_multiplier"), "Invalid gas limit multiplier", "Gas limit multiplier should be at least 1")
+	}
+	if !c.GasLimit.IsNull() && !c.GasLimitMultiplier.IsNull() {
+		diags.AddAttributeError(path.Root("gas_limit_multiplier"), "Invalid gas limit configuration", "`gas_limit` and `gas_limit_multiplier` cannot be used together")
+	}
+	if !c.GasPrice.IsNull() && (!c.MaxFeePerGas.IsNull() || !c.MaxPriorityFeePerGas.IsNull()) {
+		diags.AddAttributeError(
+			path.Root("gas_price"),
+			"Invalid fee configuration",
+			"Legacy `gas_price` cannot be used together with EIP-1559 `max_fee_per_gas` and `max_priority_fee_per_gas`",
+		)
+	}
+
+	maxFee := parseFee(path.Root("max_fee_per_gas"), c.MaxFeePerGas, diags)
+	maxPriorityFee := parseFee(path.Root("max_priority_fee_per_gas"), c.MaxPriorityFeePerGas, diags)
+	parseFee(path.Root("gas_price"), c.GasPrice, diags)
+	if maxFee != nil && maxPriorityFee != nil && maxFee.Cmp(maxPriorityFee) < 0 {
+		diags.AddAttributeError(
+			path.Root("max_priority_fee_per_gas"),
+			"Invalid fee configuration",
+			fmt.Sprintf("Max priority fee per gas %v wei exceeds max fee per gas %v wei", maxPriorityFee, maxFee),
+		)
+	}
+}
+
+// withDefaults fills gas limit and fee groups which are not configured from provider defaults.
+// Groups are taken as a whole, so that e.g. resource `gas_price` is not combined with
+// provider `max_fee_per_gas`.
+func (c feeConfig) withDefaults(defaults feeConfig) feeConfig {
+	if c.GasLimit.IsNull() && c.GasLimitMultiplier.IsNull() {
+		c.GasLimit = defaults.GasLimit
+		c.GasLimitMultiplier = defaults.GasLimitMultiplier
+	}
+	if c.GasPrice.IsNull() && c.MaxFeePerGas.IsNull() && c.MaxPriorityFeePerGas.IsNull() {
+		c.GasPrice = defaults.GasPrice
+		c.MaxFeePerGas = defaults.MaxFeePerGas
+		c.MaxPriorityFeePerGas = defaults.MaxPriorityFeePerGas
+	}
+	return c
+}
+
+// resolve converts known attribute values to transaction fees.
+func (c feeConfig) resolve(diags *diag.Diagnostics) txFees {
+	fees := txFees{
+		gasLimitMultiplier: c.GasLimitMultiplier.ValueFloat64(),
+		gasPrice:           parseFee(path.Root("gas_price"), c.GasPrice, diags),
+		gasFeeCap:          parseFee(path.Root("max_fee_per_gas"), c.MaxFeePerGas, diags),
+		gasTipCap:          parseFee(path.Root("max_priority_fee_per_gas"), c.MaxPriorityFeePerGas, diags),
+	}
+	if !c.GasLimit.IsNull() {
+		fees.gasLimit = uint64(c.GasLimit.ValueInt64())
+	}
+	return fees
+}
+
+func parseFee(attrPath path.Path, value types.String, diags *diag.Diagnostics) *big.Int {
+	if value.IsNull() || value.IsUnknown() {
+		return nil
+	}
+	fee, err := utils.ParseWei(value.ValueString())
+	if err != nil {
+		diags.AddAttributeError(attrPath, "Invalid fee value", err.Error())
+		return nil
+	}
+	return fee
+}
+
+// apply sets gas limit and fees of the transaction options. With gas limit multiplier the transaction
+// is prepared with build once to estimate gas, which is then multiplied.
+func (f txFees) apply(ctx context.Context, opts *bind.TransactOpts,
+	build func(opts *bind.TransactOpts) (*ethTypes.Transaction, error)) error {
+	opts.GasLimit = f.gasLimit
+	opts.GasPrice = f.gasPrice
+	opts.GasFeeCap = f.gasFeeCap
+	opts.GasTipCap = f.gasTipCap
+
+	if f.gasLimitMultiplier == 0 {
+		return nil
+	}
+
+	estimateOpts := *opts
+	estimateOpts.Signer = func(_ common.Address, tx *ethTypes.Transaction) (*ethTypes.Transaction, error) {
+		return tx, nil
+	}
+	tx, err := build(&estimateOpts)
+	if err != nil {
+		return err
+	}
+	opts.GasLimit = uint64(float64(tx.Gas()) * f.gasLimitMultiplier)
+	tflog.Debug(ctx, fmt.Sprintf("Estimated gas %d, using gas limit %d", tx.Gas(), opts.GasLimit))
+	return nil
+}
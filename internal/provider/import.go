@@ -0,0 +1,61 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+// findCreation looks up the transaction which deployed the contract. The block where the code appeared is
+// found with binary search, which requires the node to serve historical state. Contracts created by
+// other contracts are not found, as only transactions creating the contract directly are checked.
+func findCreation(ctx context.Context, client EvmClient, address common.Address) (*ethTypes.Transaction, *ethTypes.Receipt, error) {
+	header, err := client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot retrieve latest block: %w", err)
+	}
+
+	low, high := uint64(0), header.Number.Uint64()
+	for low < high {
+		middle := low + (high-low)/2
+		code, err := client.CodeAt(ctx, address, new(big.Int).SetUint64(middle))
+		if err != nil {
+			return nil, nil, fmt.Errorf("cannot retrieve code at block %d: %w", middle, err)
+		}
+		if len(code) > 0 {
+			high = middle
+		} else {
+			low = middle + 1
+		}
+	}
+
+	block, err := client.BlockByNumber(ctx, new(big.Int).SetUint64(low))
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot retrieve block %d: %w", low, err)
+	}
+	for _, tx := range block.Transactions() {
+		if tx.To() != nil {
+			continue
+		}
+		receipt, err := client.TransactionReceipt(ctx, tx.Hash())
+		if err != nil {
+			return nil, nil, fmt.Errorf("cannot retrieve receipt of %s: %w", tx.Hash().Hex(), err)
+		}
+		if receipt.ContractAddress == address {
+			return tx, receipt, nil
+		}
+	}
+	return nil, nil, fmt.Errorf("no transaction in block %d deploys %s directly, it is probably created by another contract", low, address.Hex())
+}
+
+// txSender recovers sender of the transaction.
+func txSender(ctx context.Context, client EvmClient, tx *ethTypes.Transaction) (common.Address, error) {
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("cannot retrieve chain ID: %w", err)
+	}
+	return ethTypes.Sender(ethTypes.LatestSignerForChainID(chainID), tx)
+}
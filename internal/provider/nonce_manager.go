@@ -0,0 +1,71 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// nonceManager allocates nonces for transactions sent by the provider. Transactions of the same
+// signer are sent one at a time in nonce order, so resources applied in parallel don't compete for
+// the pending nonce, while waiting for transactions to be mined still happens concurrently.
+type nonceManager struct {
+	mu       sync.Mutex
+	accounts map[common.Address]*accountNonce
+}
+
+// accountNonce tracks the next nonce of a single signer.
+type accountNonce struct {
+	mu sync.Mutex
+	// synced is false until the nonce is read from the chain or after it has to be reconciled
+	synced bool
+	next   uint64
+}
+
+func newNonceManager() *nonceManager {
+	return &nonceManager{accounts: map[common.Address]*accountNonce{}}
+}
+
+func (m *nonceManager) account(address common.Address) *accountNonce {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	account, ok := m.accounts[address]
+	if !ok {
+		account = &accountNonce{}
+		m.accounts[address] = account
+	}
+	return account
+}
+
+// send calls sendFn with the next nonce of the signer, holding the signer lock until the node accepts
+// the transaction. The nonce is consumed only when sendFn succeeds, on errors the nonce is read
+// from the chain again before the next transaction.
+func (m *nonceManager) send(ctx context.Context, client EvmClient, address common.Address, sendFn func(nonce uint64) error) error {
+	account := m.account(address)
+	account.mu.Lock()
+	defer account.mu.Unlock()
+
+	if !account.synced {
+		pending, err := client.PendingNonceAt(ctx, address)
+		if err != nil {
+			return fmt.Errorf("cannot retrieve nonce of %s: %w", address.Hex(), err)
+		}
+		account.next = pending
+		account.synced = true
+	}
+
+	nonce := account.next
+	tflog.Debug(ctx, fmt.Sprintf("Sending transaction from %s with nonce %d", address.Hex(), nonce))
+
+	if err := sendFn(nonce); err != nil {
+		account.synced = false
+		return err
+	}
+
+	account.next = nonce + 1
+	return nil
+}
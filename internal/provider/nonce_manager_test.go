@@ -0,0 +1,91 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNonceManagerParallelSend(t *testing.T) {
+	ctx := context.Background()
+	privateKey, err := crypto.HexToECDSA(faucetPk)
+	if err != nil {
+		t.Fatalf("Cannot parse faucet key: %v", err)
+	}
+	chainID, _ := testClient.ChainID(ctx)
+	gasPrice, _ := testClient.SuggestGasPrice(ctx)
+	start, _ := testClient.PendingNonceAt(ctx, faucetAddr)
+	recipient := common.HexToAddress("0x000000000000000000000000000000000000dead")
+
+	manager := newNonceManager()
+	const transactions = 20
+	nonces := make([]uint64, transactions)
+	errs := make([]error, transactions)
+
+	var wg sync.WaitGroup
+	for i := 0; i < transactions; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = manager.send(ctx, testClient, faucetAddr, func(nonce uint64) error {
+				tx, err := types.SignTx(
+					types.NewTransaction(nonce, recipient, big.NewInt(1), 21000, gasPrice, nil),
+					types.LatestSignerForChainID(chainID),
+					privateKey,
+				)
+				if err != nil {
+					return err
+				}
+				nonces[i] = nonce
+				return testClient.SendTransaction(ctx, tx)
+			})
+		}(i)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		assert.NoError(t, err)
+	}
+	sort.Slice(nonces, func(i, j int) bool { return nonces[i] < nonces[j] })
+	for i, nonce := range nonces {
+		assert.Equal(t, start+uint64(i), nonce)
+	}
+
+	pending, _ := testClient.PendingNonceAt(ctx, faucetAddr)
+	assert.Equal(t, start+transactions, pending)
+}
+
+func TestNonceManagerReconcile(t *testing.T) {
+	ctx := context.Background()
+	start, _ := testClient.PendingNonceAt(ctx, faucetAddr)
+	manager := newNonceManager()
+
+	err := manager.send(ctx, testClient, faucetAddr, func(nonce uint64) error {
+		assert.Equal(t, start, nonce)
+		return nil
+	})
+	assert.NoError(t, err)
+
+	// Nonce is consumed, but the transaction never reached the node
+	sendErr := errors.New("nonce too high")
+	err = manager.send(ctx, testClient, faucetAddr, func(nonce uint64) error {
+		assert.Equal(t, start+1, nonce)
+		return sendErr
+	})
+	assert.ErrorIs(t, err, sendErr)
+
+	// Nonce is read from the chain again after the failure
+	err = manager.send(ctx, testClient, faucetAddr, func(nonce uint64) error {
+		assert.Equal(t, start, nonce)
+		return nil
+	})
+	assert.NoError(t, err)
+}
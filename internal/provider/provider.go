@@ -2,16 +2,21 @@ package provider
 
 import (
 	"context"
+	"fmt"
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 var _ provider.Provider = &EvmProvider{}
@@ -20,17 +25,71 @@ type EvmClient interface {
 	bind.ContractBackend
 	bind.DeployBackend
 	ChainID(context.Context) (*big.Int, error)
+	// BlockByNumber and TransactionByHash look up existing transactions on import
+	BlockByNumber(ctx context.Context, number *big.Int) (*ethTypes.Block, error)
+	TransactionByHash(ctx context.Context, hash common.Hash) (tx *ethTypes.Transaction, isPending bool, err error)
+	// BalanceAt checks funds of the accounts funded by the provider
+	BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error)
+	// StorageAt reads ERC-1967 slots of the deployed proxies
+	StorageAt(ctx context.Context, account common.Address, key common.Hash, blockNumber *big.Int) ([]byte, error)
+	// CallContext performs raw JSON-RPC call for node specific methods
+	CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error
+}
+
+// rpcClient implements EvmClient on top of the JSON-RPC connection to the node.
+type rpcClient struct {
+	*ethclient.Client
+	rpc *rpc.Client
+}
+
+func (c rpcClient) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	return c.rpc.CallContext(ctx, result, method, args...)
 }
 
 // EvmProvider defines the provider implementation.
 type EvmProvider struct {
 	version string
 	client  EvmClient
+	nonces  *nonceManager
 }
 
 // EvmProviderModel describes the provider data model.
 type EvmProviderModel struct {
-	NodeUrl types.String `tfsdk:"node_url"`
+	NodeUrl types.String          `tfsdk:"node_url"`
+	Signers []providerSignerModel `tfsdk:"signers"`
+	Retry   *providerRetryModel   `tfsdk:"retry"`
+
+	GasLimit             types.Int64   `tfsdk:"gas_limit"`
+	GasLimitMultiplier   types.Float64 `tfsdk:"gas_limit_multiplier"`
+	MaxFeePerGas         types.String  `tfsdk:"max_fee_per_gas"`
+	MaxPriorityFeePerGas types.String  `tfsdk:"max_priority_fee_per_gas"`
+	GasPrice             types.String  `tfsdk:"gas_price"`
+}
+
+func (m EvmProviderModel) feeConfig() feeConfig {
+	return feeConfig{m.GasLimit, m.GasLimitMultiplier, m.MaxFeePerGas, m.MaxPriorityFeePerGas, m.GasPrice}
+}
+
+// providerSignerModel describes a named signer shared by resources.
+type providerSignerModel struct {
+	Name             types.String `tfsdk:"name"`
+	PrivateKey       types.String `tfsdk:"private_key"`
+	Keystore         types.String `tfsdk:"keystore"`
+	KeystorePassword types.String `tfsdk:"keystore_password"`
+	Env              types.String `tfsdk:"env"`
+	RemoteUrl        types.String `tfsdk:"remote_url"`
+	RemoteMethod     types.String `tfsdk:"remote_method"`
+	Address          types.String `tfsdk:"address"`
+}
+
+// providerData is passed to resources on configuration.
+type providerData struct {
+	client  EvmClient
+	signers map[string]*txSigner
+	nonces  *nonceManager
+	retry   retryPolicy
+	// fees are default gas limit and fees of resource transactions
+	fees feeConfig
 }
 
 func (p *EvmProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
@@ -46,6 +105,91 @@ func (p *EvmProvider) Schema(ctx context.Context, req provider.SchemaRequest, re
 				Optional:            node_url_optional,
 				Required:            !node_url_optional,
 			},
+			"gas_limit": schema.Int64Attribute{
+				MarkdownDescription: "Default for resources. " + gasLimitDescription + feeDefaultsDescription,
+				Optional:            true,
+			},
+			"gas_limit_multiplier": schema.Float64Attribute{
+				MarkdownDescription: "Default for resources. " + gasLimitMultiplierDescription + feeDefaultsDescription,
+				Optional:            true,
+			},
+			"max_fee_per_gas": schema.StringAttribute{
+				MarkdownDescription: "Default for resources. " + maxFeePerGasDescription + feeDefaultsDescription,
+				Optional:            true,
+			},
+			"max_priority_fee_per_gas": schema.StringAttribute{
+				MarkdownDescription: "Default for resources. " + maxPriorityFeePerGasDescription + feeDefaultsDescription,
+				Optional:            true,
+			},
+			"gas_price": schema.StringAttribute{
+				MarkdownDescription: "Default for resources. " + gasPriceDescription + feeDefaultsDescription,
+				Optional:            true,
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"retry": schema.SingleNestedBlock{
+				MarkdownDescription: "Retry policy for node requests sending transactions. Requests failed with timeouts, rate limiting (HTTP 429), server errors (HTTP 5xx) or transaction pool races (e.g. nonce too low) are retried with exponential backoff, other errors fail immediately",
+				Attributes: map[string]schema.Attribute{
+					"max_attempts": schema.Int64Attribute{
+						MarkdownDescription: fmt.Sprintf("Maximum number of attempts including the first one. Defaults to %d", defaultRetryMaxAttempts),
+						Optional:            true,
+					},
+					"backoff": schema.StringAttribute{
+						MarkdownDescription: fmt.Sprintf("Delay before the first retry (e.g. `500ms`), doubled for each subsequent retry. Defaults to `%v`", defaultRetryBackoff),
+						Optional:            true,
+					},
+					"max_backoff": schema.StringAttribute{
+						MarkdownDescription: fmt.Sprintf("Maximum delay between retries. Defaults to `%v`", defaultRetryMaxBackoff),
+						Optional:            true,
+					},
+					"jitter": schema.Float64Attribute{
+						MarkdownDescription: fmt.Sprintf("Fraction of the delay, between 0 and 1, by which each delay is randomly increased or decreased. Defaults to %v", defaultRetryJitter),
+						Optional:            true,
+					},
+				},
+			},
+			"signers": schema.ListNestedBlock{
+				MarkdownDescription: "Named transaction signers which resources reference with `signer_name`. Keys are resolved once when the provider is configured and never stored in resource state",
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							MarkdownDescription: "Unique signer name referenced by resource `signer_name` attribute",
+							Required:            true,
+						},
+						"private_key": schema.StringAttribute{
+							MarkdownDescription: "Signer private key (32-byte hex, no `0x` prefix). Conflicts with `keystore`, `env` and `remote_url`",
+							Optional:            true,
+							Sensitive:           true,
+						},
+						"keystore": schema.StringAttribute{
+							MarkdownDescription: "Encrypted V3 keystore, either JSON content or a path to the keystore file. Requires `keystore_password`, conflicts with `private_key`, `env` and `remote_url`",
+							Optional:            true,
+							Sensitive:           true,
+						},
+						"keystore_password": schema.StringAttribute{
+							MarkdownDescription: "Password used to decrypt `keystore`",
+							Optional:            true,
+							Sensitive:           true,
+						},
+						"env": schema.StringAttribute{
+							MarkdownDescription: "Name of the environment variable holding signer private key (32-byte hex, no `0x` prefix). Conflicts with `private_key`, `keystore` and `remote_url`",
+							Optional:            true,
+						},
+						"remote_url": schema.StringAttribute{
+							MarkdownDescription: "URL of the remote JSON-RPC signer (e.g. Clef or Web3Signer) holding the key. Transactions are built locally, signed remotely and broadcast to `node_url`. Requires `address`, conflicts with `private_key`, `keystore` and `env`",
+							Optional:            true,
+						},
+						"remote_method": schema.StringAttribute{
+							MarkdownDescription: "Signing method of the remote signer, either `eth_signTransaction` (Web3Signer, default) or `account_signTransaction` (Clef)",
+							Optional:            true,
+						},
+						"address": schema.StringAttribute{
+							MarkdownDescription: "Address of the remote signer account (20-byte hex with `0x` prefix)",
+							Optional:            true,
+						},
+					},
+				},
+			},
 		},
 	}
 }
@@ -69,13 +213,39 @@ func (p *EvmProvider) Configure(ctx context.Context, req provider.ConfigureReque
 		}
 	}
 
+	retry := config.Retry.policy(path.Root("retry"), &resp.Diagnostics)
+	config.feeConfig().validate(&resp.Diagnostics)
+
+	signers := make(map[string]*txSigner, len(config.Signers))
+	for i, signerConfig := range config.Signers {
+		signerPath := path.Root("signers").AtListIndex(i)
+		name := signerConfig.Name.ValueString()
+		if signerConfig.hasUnknownValues() {
+			// Unknown values are only possible while planning, when signers are not used
+			tflog.Info(ctx, fmt.Sprintf("Skipping signer '%s' with values unknown until apply", name))
+			continue
+		}
+		if _, ok := signers[name]; ok {
+			resp.Diagnostics.AddAttributeError(
+				signerPath.AtName("name"),
+				"Duplicate signer name",
+				fmt.Sprintf("Signer '%s' is configured more than once", name),
+			)
+			continue
+		}
+		signer := signerConfig.resolve(ctx, signerPath, &resp.Diagnostics)
+		if signer != nil {
+			tflog.Info(ctx, fmt.Sprintf("Configured signer '%s' with address %s", name, signer.address.Hex()))
+			signers[name] = signer
+		}
+	}
+
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
 	if p.client == nil {
-		var client EvmClient
-		client, err := ethclient.DialContext(ctx, config.NodeUrl.ValueString())
+		client, err := rpc.DialContext(ctx, config.NodeUrl.ValueString())
 		if err != nil {
 			resp.Diagnostics.AddError(
 				"Unable to Create EVM RPC Client",
@@ -83,18 +253,29 @@ func (p *EvmProvider) Configure(ctx context.Context, req provider.ConfigureReque
 			)
 			return
 		}
-		p.client = client
+		p.client = rpcClient{ethclient.NewClient(client), client}
 	}
 
-	resp.DataSourceData = p.client
-	resp.ResourceData = p.client
+	data := providerData{
+		client:  p.client,
+		signers: signers,
+		nonces:  p.nonces,
+		retry:   retry,
+		fees:    config.feeConfig(),
+	}
+	resp.DataSourceData = data
+	resp.ResourceData = data
 }
 
 func (p *EvmProvider) Resources(ctx context.Context) []func() resource.Resource {
 	return []func() resource.Resource{
 		NewRandomPkResource,
+		NewHdWalletResource,
 		NewContractResource,
 		NewContractTxResource,
+		NewTransferResource,
+		NewCreate2FactoryResource,
+		NewProxyResource,
 	}
 }
 
@@ -108,6 +289,7 @@ func New(version string, client EvmClient) func() provider.Provider {
 		return &EvmProvider{
 			version,
 			client,
+			newNonceManager(),
 		}
 	}
 }
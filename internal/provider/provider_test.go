@@ -2,21 +2,106 @@ package provider
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"fmt"
 	"math/big"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"testing"
 
 	ethereum "github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/eth/ethconfig"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	"github.com/ethereum/go-ethereum/node"
+	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+
+	"terraform-provider-evm/internal/utils"
 )
 
 type SimulatedClient struct {
-	b *backends.SimulatedBackend
+	b        *backends.SimulatedBackend
+	accounts *devNodeAccounts
+	codes    *devNodeCodes
+}
+
+// devNodeCodes emulates code of accounts replaced with development node API.
+type devNodeCodes struct {
+	mu    sync.Mutex
+	codes map[common.Address][]byte
+}
+
+// devNodeAccounts emulates accounts managed by a development node, which can only send
+// transactions from unlocked or impersonated accounts.
+type devNodeAccounts struct {
+	mu           sync.Mutex
+	keys         map[common.Address]*ecdsa.PrivateKey
+	unlocked     map[common.Address]bool
+	impersonated map[common.Address]bool
+}
+
+// CallContext implements EvmClient, emulating development node API.
+func (c SimulatedClient) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	c.accounts.mu.Lock()
+	defer c.accounts.mu.Unlock()
+
+	switch method {
+	case "anvil_impersonateAccount", "hardhat_impersonateAccount":
+		c.accounts.impersonated[args[0].(common.Address)] = true
+		return nil
+	case "anvil_stopImpersonatingAccount", "hardhat_stopImpersonatingAccount":
+		delete(c.accounts.impersonated, args[0].(common.Address))
+		return nil
+	case "anvil_setCode", "hardhat_setCode":
+		c.codes.mu.Lock()
+		defer c.codes.mu.Unlock()
+		c.codes.codes[args[0].(common.Address)] = hexutil.MustDecode(args[1].(string))
+		return nil
+	case "eth_sendTransaction":
+		txArgs := args[0].(utils.TransactionArgs)
+		if !c.accounts.unlocked[txArgs.From] && !c.accounts.impersonated[txArgs.From] {
+			return fmt.Errorf("no signer for account %s", txArgs.From.Hex())
+		}
+		tx, err := signTransactionArgs(c.accounts.keys[txArgs.From], txArgs)
+		if err != nil {
+			return err
+		}
+		if err := c.SendTransaction(ctx, tx); err != nil {
+			return err
+		}
+		*result.(*common.Hash) = tx.Hash()
+		return nil
+	}
+	return fmt.Errorf("method %s is not supported by simulated client", method)
+}
+
+// BalanceAt implements EvmClient.
+func (c SimulatedClient) BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error) {
+	return c.b.BalanceAt(ctx, account, blockNumber)
+}
+
+// StorageAt implements EvmClient.
+func (c SimulatedClient) StorageAt(ctx context.Context, account common.Address, key common.Hash, blockNumber *big.Int) ([]byte, error) {
+	return c.b.StorageAt(ctx, account, key, blockNumber)
+}
+
+// BlockByNumber implements EvmClient.
+func (c SimulatedClient) BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error) {
+	return c.b.BlockByNumber(ctx, number)
 }
 
 // CallContract implements EvmClient.
@@ -31,6 +116,12 @@ func (SimulatedClient) ChainID(context.Context) (*big.Int, error) {
 
 // CodeAt implements EvmClient.
 func (c SimulatedClient) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	c.codes.mu.Lock()
+	code, ok := c.codes.codes[contract]
+	c.codes.mu.Unlock()
+	if ok {
+		return code, nil
+	}
 	return c.b.CodeAt(ctx, contract, blockNumber)
 }
 
@@ -83,6 +174,11 @@ func (c SimulatedClient) SuggestGasTipCap(ctx context.Context) (*big.Int, error)
 	return c.b.SuggestGasTipCap(ctx)
 }
 
+// TransactionByHash implements EvmClient.
+func (c SimulatedClient) TransactionByHash(ctx context.Context, txHash common.Hash) (*types.Transaction, bool, error) {
+	return c.b.TransactionByHash(ctx, txHash)
+}
+
 // TransactionReceipt implements EvmClient.
 func (c SimulatedClient) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
 	return c.b.TransactionReceipt(ctx, txHash)
@@ -91,7 +187,10 @@ func (c SimulatedClient) TransactionReceipt(ctx context.Context, txHash common.H
 var faucetAddr common.Address
 var faucetPk string
 
-func createSimulatedClient() EvmClient {
+// impersonatedAddr is funded account which key is only known to the simulated node.
+var impersonatedAddr common.Address
+
+func createSimulatedClient() SimulatedClient {
 	privateKey, err := crypto.GenerateKey()
 	if err != nil {
 		panic("Cannot generate a random key")
@@ -100,14 +199,246 @@ func createSimulatedClient() EvmClient {
 	privateKeyBytes := crypto.FromECDSA(privateKey)
 	faucetPk = hexutil.Encode(privateKeyBytes)[2:]
 
+	impersonatedKey, err := crypto.GenerateKey()
+	if err != nil {
+		panic("Cannot generate a random key")
+	}
+	impersonatedAddr = crypto.PubkeyToAddress(impersonatedKey.PublicKey)
+
 	addr := map[common.Address]core.GenesisAccount{
-		faucetAddr: {Balance: new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(9))},
+		faucetAddr:       {Balance: new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(9))},
+		impersonatedAddr: {Balance: new(big.Int).Lsh(big.NewInt(1), 128)},
 	}
 	alloc := core.GenesisAlloc(addr)
+	accounts := &devNodeAccounts{
+		keys: map[common.Address]*ecdsa.PrivateKey{
+			faucetAddr:       privateKey,
+			impersonatedAddr: impersonatedKey,
+		},
+		unlocked:     map[common.Address]bool{faucetAddr: true},
+		impersonated: map[common.Address]bool{},
+	}
+	b := simulated.NewBackend(alloc, simulated.WithBlockGasLimit(9000000), func(nodeConf *node.Config, _ *ethconfig.Config) {
+		// Development nodes accept presigned transactions without chain ID, e.g. the CREATE2 factory deployment
+		nodeConf.AllowUnprotectedTxs = true
+	})
 	//nolint:all
-	return SimulatedClient{backends.NewSimulatedBackend(alloc, 9000000)}
+	backend := &backends.SimulatedBackend{Backend: b, Client: b.Client()}
+	return SimulatedClient{backend, accounts, &devNodeCodes{codes: map[common.Address][]byte{}}}
+}
+
+// writeFaucetKeystore stores faucet private key as a V3 keystore file encrypted with the given password.
+func writeFaucetKeystore(t *testing.T, password string) string {
+	privateKey, err := crypto.HexToECDSA(faucetPk)
+	if err != nil {
+		t.Fatalf("Error converting string to private key: %v", err)
+	}
+	keyJson, err := keystore.EncryptKey(&keystore.Key{
+		Address:    faucetAddr,
+		PrivateKey: privateKey,
+	}, password, keystore.LightScryptN, keystore.LightScryptP)
+	if err != nil {
+		t.Fatalf("Cannot encrypt faucet key: %v", err)
+	}
+	keyPath := filepath.Join(t.TempDir(), "faucet.json")
+	if err := os.WriteFile(keyPath, keyJson, 0600); err != nil {
+		t.Fatalf("Cannot write keystore file: %v", err)
+	}
+	return keyPath
+}
+
+// remoteSignerService is a stand-in for a remote signer signing transactions with a fixture key.
+type remoteSignerService struct {
+	key *ecdsa.PrivateKey
+}
+
+// signTransactionArgs signs transaction described by JSON-RPC arguments.
+func signTransactionArgs(key *ecdsa.PrivateKey, args utils.TransactionArgs) (*types.Transaction, error) {
+	var txData types.TxData
+	if args.MaxFeePerGas != nil {
+		txData = &types.DynamicFeeTx{
+			ChainID:   args.ChainID.ToInt(),
+			Nonce:     uint64(args.Nonce),
+			GasTipCap: args.MaxPriorityFeePerGas.ToInt(),
+			GasFeeCap: args.MaxFeePerGas.ToInt(),
+			Gas:       uint64(args.Gas),
+			To:        args.To,
+			Value:     args.Value.ToInt(),
+			Data:      args.Data,
+		}
+	} else {
+		txData = &types.LegacyTx{
+			Nonce:    uint64(args.Nonce),
+			GasPrice: args.GasPrice.ToInt(),
+			Gas:      uint64(args.Gas),
+			To:       args.To,
+			Value:    args.Value.ToInt(),
+			Data:     args.Data,
+		}
+	}
+	return types.SignNewTx(key, types.LatestSignerForChainID(args.ChainID.ToInt()), txData)
+}
+
+func (s *remoteSignerService) sign(args utils.TransactionArgs) (hexutil.Bytes, error) {
+	tx, err := signTransactionArgs(s.key, args)
+	if err != nil {
+		return nil, err
+	}
+	return tx.MarshalBinary()
 }
 
+// web3SignerService implements eth_signTransaction returning raw transaction.
+type web3SignerService struct{ remoteSignerService }
+
+func (s *web3SignerService) SignTransaction(args utils.TransactionArgs) (hexutil.Bytes, error) {
+	return s.sign(args)
+}
+
+// clefService implements account_signTransaction returning raw transaction with its JSON representation.
+type clefService struct{ remoteSignerService }
+
+func (s *clefService) SignTransaction(args utils.TransactionArgs) (map[string]interface{}, error) {
+	raw, err := s.sign(args)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"raw": raw}, nil
+}
+
+// startRemoteSigner serves remote signer JSON-RPC API signing with the private key.
+func startRemoteSigner(t *testing.T, pk string) string {
+	privateKey, err := crypto.HexToECDSA(pk)
+	if err != nil {
+		t.Fatalf("Error converting string to private key: %v", err)
+	}
+	server := rpc.NewServer()
+	if err := server.RegisterName("eth", &web3SignerService{remoteSignerService{privateKey}}); err != nil {
+		t.Fatalf("Cannot register signer service: %v", err)
+	}
+	if err := server.RegisterName("account", &clefService{remoteSignerService{privateKey}}); err != nil {
+		t.Fatalf("Cannot register signer service: %v", err)
+	}
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(func() {
+		httpServer.Close()
+		server.Stop()
+	})
+	return httpServer.URL
+}
+
+// testClient is the simulated node shared by all tests.
+var testClient = createSimulatedClient()
+
 var testAccProtoV6ProviderFactories = map[string]func() (tfprotov6.ProviderServer, error){
-	"evm": providerserver.NewProtocol6WithError(New("test", createSimulatedClient())()),
+	"evm": providerserver.NewProtocol6WithError(New("test", testClient)()),
+}
+
+// testCheckTransaction checks the transaction which hash is stored in the resource attribute.
+func testCheckTransaction(name string, key string, check func(tx *types.Transaction) error) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("resource %s not found", name)
+		}
+		tx, _, err := testClient.b.TransactionByHash(context.Background(), common.HexToHash(rs.Primary.Attributes[key]))
+		if err != nil {
+			return fmt.Errorf("cannot find transaction of %s: %w", name, err)
+		}
+		return check(tx)
+	}
+}
+
+// testCheckBalance checks balance in wei of the address stored in the resource attribute.
+func testCheckBalance(name string, key string, expected *big.Int) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("resource %s not found", name)
+		}
+		balance, err := testClient.b.BalanceAt(context.Background(), common.HexToAddress(rs.Primary.Attributes[key]), nil)
+		if err != nil {
+			return err
+		}
+		if balance.Cmp(expected) != 0 {
+			return fmt.Errorf("expected %s balance %v, got %v", name, expected, balance)
+		}
+		return nil
+	}
+}
+
+// testImportStateAttr returns the attribute of the resource as the import ID.
+func testImportStateAttr(name string, key string) resource.ImportStateIdFunc {
+	return func(s *terraform.State) (string, error) {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return "", fmt.Errorf("resource %s not found", name)
+		}
+		return rs.Primary.Attributes[key], nil
+	}
+}
+
+// expectPlannedValue is a plan check asserting that the attribute of the resource is known at plan time
+// and equals the value, which is referenced to be set by checks of the previous steps.
+type expectPlannedValue struct {
+	resourceAddress string
+	attribute       string
+	value           *string
+}
+
+func (e expectPlannedValue) CheckPlan(_ context.Context, req plancheck.CheckPlanRequest, resp *plancheck.CheckPlanResponse) {
+	for _, rc := range req.Plan.ResourceChanges {
+		if rc.Address != e.resourceAddress {
+			continue
+		}
+		after, ok := rc.Change.After.(map[string]interface{})
+		if !ok || after[e.attribute] != *e.value {
+			resp.Error = fmt.Errorf("%s.%s is planned as %v, expected %s", e.resourceAddress, e.attribute, after[e.attribute], *e.value)
+		}
+		return
+	}
+	resp.Error = fmt.Errorf("%s - Resource not found in plan ResourceChanges", e.resourceAddress)
+}
+
+// expectKnownPlan is a plan check asserting that all attributes of the resource are known at plan time.
+type expectKnownPlan struct {
+	resourceAddress string
+}
+
+func (e expectKnownPlan) CheckPlan(_ context.Context, req plancheck.CheckPlanRequest, resp *plancheck.CheckPlanResponse) {
+	for _, rc := range req.Plan.ResourceChanges {
+		if rc.Address != e.resourceAddress {
+			continue
+		}
+		unknown, _ := rc.Change.AfterUnknown.(map[string]interface{})
+		for attribute, value := range unknown {
+			if value == true {
+				resp.Error = fmt.Errorf("%s.%s is unknown at plan time", e.resourceAddress, attribute)
+				return
+			}
+		}
+		return
+	}
+	resp.Error = fmt.Errorf("%s - Resource not found in plan ResourceChanges", e.resourceAddress)
+}
+
+// expectPriorValue is a plan check asserting that the attribute of the resource is stored in the prior state,
+// e.g. of the resource tainted by the failed apply.
+type expectPriorValue struct {
+	resourceAddress string
+	attribute       string
+	value           *regexp.Regexp
+}
+
+func (e expectPriorValue) CheckPlan(_ context.Context, req plancheck.CheckPlanRequest, resp *plancheck.CheckPlanResponse) {
+	for _, rc := range req.Plan.ResourceChanges {
+		if rc.Address != e.resourceAddress {
+			continue
+		}
+		before, ok := rc.Change.Before.(map[string]interface{})
+		if value, _ := before[e.attribute].(string); !ok || !e.value.MatchString(value) {
+			resp.Error = fmt.Errorf("%s.%s is stored as %v, expected to match %s", e.resourceAddress, e.attribute, before[e.attribute], e.value)
+		}
+		return
+	}
+	resp.Error = fmt.Errorf("%s - Resource not found in plan ResourceChanges", e.resourceAddress)
 }
@@ -2,23 +2,31 @@ package provider
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
-	"strings"
+	"math/big"
 	"terraform-provider-evm/internal/utils"
-	"time"
 
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core"
-	"github.com/ethereum/go-ethereum/core/txpool"
 	ethTypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
@@ -27,7 +35,7 @@ func NewContractResource() resource.Resource {
 }
 
 type contractResource struct {
-	client EvmClient
+	providerData
 }
 
 func (*contractResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -37,27 +45,135 @@ func (*contractResource) Metadata(_ context.Context, req resource.MetadataReques
 func (*contractResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		MarkdownDescription: "Resource used to deploy new Smart Contracts.",
-		Attributes: map[string]schema.Attribute{
+		Version:             1,
+		Attributes: eventAttributes(receiptAttributes(feeAttributes(signerAttributes(map[string]schema.Attribute{
 			"artifact": schema.StringAttribute{
-				MarkdownDescription: "Content of Hardhat compiled artifact containing ABI and binary in JSON format",
-				Required:            true,
+				MarkdownDescription: "Content of the compiled artifact containing ABI and binary in JSON format, which is stored in the state. Conflicts with `artifact_path`",
+				Optional:            true,
 				Sensitive:           true,
 			},
-			"signer": schema.StringAttribute{
-				MarkdownDescription: "Deploy transaction signer private key (32-byte hex, no `0x` prefix). Can reference `evm_random_pk.pk` resource",
-				Required:            true,
-				Sensitive:           true,
+			"artifact_path": schema.StringAttribute{
+				MarkdownDescription: "Path to the compiled artifact, relative to the working directory (e.g. `${path.module}/artifacts/Token.json`). Unlike `artifact`, only hashes and the ABI of the artifact are stored in the state. Conflicts with `artifact`",
+				Optional:            true,
+			},
+			"artifact_format": schema.StringAttribute{
+				MarkdownDescription: "Format of the artifact: `hardhat`, `foundry`, `truffle`, `solc` (output of `solc --combined-json abi,bin,bin-runtime`) or `vyper` (output of `vyper -f combined_json`). Detected from the artifact content by default",
+				Optional:            true,
+			},
+			"artifact_contract": schema.StringAttribute{
+				MarkdownDescription: "Contract of the `solc` or `vyper` combined output containing several contracts, given by its key: `path:Name` for solc (e.g. `src/Token.sol:Token`) or the source path for Vyper (e.g. `src/Token.vy`). Required unless the output has a single contract with bytecode",
+				Optional:            true,
+			},
+			"artifact_hash": schema.StringAttribute{
+				MarkdownDescription: "Hex encoded SHA-256 hash of the artifact",
+				Computed:            true,
+			},
+			"bytecode_hash": schema.StringAttribute{
+				MarkdownDescription: "Keccak256 hash of the artifact bytecode. Changes of the bytecode replace the contract, while metadata appended to the bytecode by the compiler is ignored, so recompiling unchanged sources doesn't deploy the contract again",
+				Computed:            true,
+			},
+			"abi": schema.StringAttribute{
+				MarkdownDescription: "Contract ABI in JSON format",
+				Computed:            true,
+			},
+			"abi_signatures": schema.ListAttribute{
+				MarkdownDescription: "Sorted signatures of the constructor, functions, events and errors declared in the ABI, which show ABI changes in the plan",
+				ElementType:         types.StringType,
+				Computed:            true,
 			},
 			"address": schema.StringAttribute{
 				MarkdownDescription: "Deployed contract address, computed after the contract is successfully deployed",
 				Computed:            true,
 			},
+			"tx_id": schema.StringAttribute{
+				MarkdownDescription: "Hash of the contract creation transaction",
+				Computed:            true,
+			},
+			"deployed_code_hash": schema.StringAttribute{
+				MarkdownDescription: "Keccak256 hash of the deployed runtime code, with values of immutable variables listed by Foundry artifacts in `deployedBytecode.immutableReferences` and compiler metadata masked. Refresh reports when the code at the address differs from it, see `current_code_hash`, and removes the contract from the state when there is no code anymore (e.g. after the development node reset)",
+				Computed:            true,
+			},
+			"current_code_hash": schema.StringAttribute{
+				MarkdownDescription: "Hash of the code at the address calculated as `deployed_code_hash` on refresh. Differs from `deployed_code_hash` while the code is replaced outside of Terraform, which is reported on every refresh",
+				Computed:            true,
+			},
 			"constructor_args": schema.ListAttribute{
-				MarkdownDescription: "String list of contract constructor arguments. See the list of supported types [here](../../README.md#deployment-and-transaction-args)",
+				MarkdownDescription: "String list of contract constructor arguments, changes replace the contract. See the list of supported types [here](../../README.md#deployment-and-transaction-args)",
 				ElementType:         types.StringType,
 				Optional:            true,
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.RequiresReplaceIf(
+						constructorArgsChanged,
+						"Contract is replaced when constructor args change",
+						"Contract is replaced when constructor args change",
+					),
+				},
+			},
+			"libraries": schema.MapAttribute{
+				MarkdownDescription: "Map of fully qualified library names (e.g. `contracts/Math.sol:Math`) to addresses of the deployed libraries, which are linked into the bytecode at the link references of the artifact. Libraries of Truffle artifacts are referenced only by the name. Every library referenced by the artifact is required and unused libraries are rejected, changes replace the contract",
+				ElementType:         types.StringType,
+				Optional:            true,
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.RequiresReplaceIf(
+						librariesChanged,
+						"Contract is replaced when linked libraries change",
+						"Contract is replaced when linked libraries change",
+					),
+				},
+			},
+			"salt": schema.StringAttribute{
+				MarkdownDescription: "Salt of the deterministic CREATE2 deployment through `create2_factory` (up to 32-byte hex with `0x` prefix, left padded with zeros). The contract address depends only on the factory, the salt and the init code, so it's known at plan time and is the same on every chain. Deployment is skipped when there is code at the address, which fails the apply only when it differs from the runtime bytecode of an artifact listing immutable references. Changes replace the contract",
+				Optional:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplaceIf(
+						create2Changed,
+						"Contract is replaced when the salt changes",
+						"Contract is replaced when the salt changes",
+					),
+				},
+			},
+			"create2_factory": schema.StringAttribute{
+				MarkdownDescription: "Address of the CREATE2 factory deploying the contract with `salt`, which takes the salt followed by the init code as calldata. Defaults to the deterministic deployment proxy `0x4e59b44847b379578588920cA78FbF26c0B4956C`, changes replace the contract",
+				Optional:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplaceIf(
+						create2Changed,
+						"Contract is replaced when the CREATE2 factory changes",
+						"Contract is replaced when the CREATE2 factory changes",
+					),
+				},
+			},
+			"predict_address": schema.BoolAttribute{
+				MarkdownDescription: "Predict `address` at plan time from the pending nonce of the signer, so resources depending on the address are planned with the known value. The prediction assumes the deployment is the next transaction of the signer, so other transactions of the signer in the same apply have to depend on this contract, and only one contract of the signer can predict its address at a time. Apply fails when the nonce of the signer moves after the plan. Ignored with `salt`",
+				Optional:            true,
+			},
+			"expected_nonce": schema.Int64Attribute{
+				MarkdownDescription: "Nonce of the signer the contract is deployed with, so the same signer deploys the contract at the same address on every chain. The address is known at plan time. Apply fails when the nonce of the signer is already above it, changes replace the contract. Conflicts with `salt`",
+				Optional:            true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplaceIf(
+						expectedNonceChanged,
+						"Contract is replaced when the expected nonce changes",
+						"Contract is replaced when the expected nonce changes",
+					),
+				},
+			},
+			"fill_nonce_gap": schema.BoolAttribute{
+				MarkdownDescription: "Send zero-value transfers of the signer to itself until its nonce reaches `expected_nonce`. Without it, apply fails when the nonce of the signer is below `expected_nonce`",
+				Optional:            true,
+			},
+			"value": schema.StringAttribute{
+				MarkdownDescription: "Amount of ETH sent to the payable constructor, in wei or with a unit suffix (e.g. `0.1 ether` or `20 gwei`), changes replace the contract. Defaults to 0",
+				Optional:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplaceIf(
+						valueChanged,
+						"Contract is replaced when the value sent to the constructor changes",
+						"Contract is replaced when the value sent to the constructor changes",
+					),
+				},
 			},
-		},
+		})))),
 	}
 }
 
@@ -67,141 +183,842 @@ func (r *contractResource) Configure(ctx context.Context, req resource.Configure
 		return
 	}
 
-	client, ok := req.ProviderData.(EvmClient)
+	data, ok := req.ProviderData.(providerData)
 
 	if !ok {
 		resp.Diagnostics.AddError(
-			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *ethclient.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected providerData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
 	}
 
-	r.client = client
+	r.providerData = data
+}
+
+func (*contractResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var model contractModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	model.signerConfig().validate(&resp.Diagnostics)
+	model.feeConfig().validate(&resp.Diagnostics)
+	parseValue(model.Value, &resp.Diagnostics)
+
+	if model.Artifact.IsNull() && model.ArtifactPath.IsNull() {
+		resp.Diagnostics.AddAttributeError(path.Root("artifact_path"), "Missing artifact", "Either `artifact` or `artifact_path` is required")
+	}
+	if !model.Artifact.IsNull() && !model.ArtifactPath.IsNull() {
+		resp.Diagnostics.AddAttributeError(path.Root("artifact_path"), "Invalid artifact configuration", "`artifact` and `artifact_path` cannot be used together")
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var abis []*abi.ABI
+	artifact := loadArtifact(model.Artifact, model.ArtifactPath, model.ArtifactFormat, model.ArtifactContract, &resp.Diagnostics)
+	if artifact != nil {
+		abis = append(abis, &artifact.abi)
+	}
+	parseLibraries(ctx, model.Libraries, artifact, &resp.Diagnostics)
+	parseSalt(model.Salt, &resp.Diagnostics)
+	parseCreate2Factory(model.Create2Factory, &resp.Diagnostics)
+	if !model.Create2Factory.IsNull() && model.Salt.IsNull() {
+		resp.Diagnostics.AddAttributeError(path.Root("create2_factory"), "Missing salt", "`create2_factory` requires `salt`")
+	}
+	if !model.ExpectedNonce.IsNull() && !model.Salt.IsNull() {
+		resp.Diagnostics.AddAttributeError(path.Root("expected_nonce"), "Invalid nonce configuration", "`expected_nonce` and `salt` cannot be used together")
+	}
+	if !model.ExpectedNonce.IsNull() && !model.ExpectedNonce.IsUnknown() && model.ExpectedNonce.ValueInt64() < 0 {
+		resp.Diagnostics.AddAttributeError(path.Root("expected_nonce"), "Invalid nonce configuration", fmt.Sprintf("Expected non-negative nonce, got %d", model.ExpectedNonce.ValueInt64()))
+	}
+	if !model.FillNonceGap.IsNull() && model.ExpectedNonce.IsNull() {
+		resp.Diagnostics.AddAttributeError(path.Root("fill_nonce_gap"), "Invalid nonce configuration", "`fill_nonce_gap` requires `expected_nonce`")
+	}
+	parseCapture(ctx, model.Capture, abis, &resp.Diagnostics)
 }
 
 func (r *contractResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
-	r.deployContract(ctx, &req.Plan, &resp.State, &resp.Diagnostics)
+	r.deployContract(ctx, &req.Plan, &resp.State, resp.Private, &resp.Diagnostics)
 }
 
-func (*contractResource) Read(context.Context, resource.ReadRequest, *resource.ReadResponse) {
+func (r *contractResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var model contractModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	address := common.HexToAddress(model.Address.ValueString())
+	code, err := r.client.CodeAt(ctx, address, nil)
+	if err != nil {
+		resp.Diagnostics.AddError("Cannot retrieve deployed code", err.Error())
+		return
+	}
+	if len(code) == 0 {
+		tflog.Warn(ctx, fmt.Sprintf("There is no code at %s, removing contract from the state", address.Hex()))
+		resp.State.RemoveResource(ctx)
+		return
+	}
 
+	codeHash := utils.CodeHash(code, model.immutableReferences(ctx, req.Private, &resp.Diagnostics)).Hex()
+	// Hash of the deployed code is kept, so the change is reported until the contract is replaced
+	if model.DeployedCodeHash.IsNull() {
+		model.DeployedCodeHash = types.StringValue(codeHash)
+	} else if model.DeployedCodeHash.ValueString() != codeHash {
+		resp.Diagnostics.AddWarning(
+			"Deployed code changed",
+			fmt.Sprintf("Code at %s has hash %s, while the deployed code had hash %s", address.Hex(), codeHash, model.DeployedCodeHash.ValueString()),
+		)
+	}
+	model.CurrentCodeHash = types.StringValue(codeHash)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, model)...)
 }
 
-func (r *contractResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	r.deployContract(ctx, &req.Plan, &resp.State, &resp.Diagnostics)
+// ModifyPlan calculates attributes of the artifact, replacing the contract when the bytecode changes, and
+// keeps attributes of the deployed contract when it is updated in place. Address of the contract deployed
+// with CREATE2 is calculated as soon as the init code is known, while address of the contract deployed
+// by the signer is predicted from its nonce when `predict_address` or `expected_nonce` is set.
+func (r *contractResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan contractModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	artifact := loadArtifact(plan.Artifact, plan.ArtifactPath, plan.ArtifactFormat, plan.ArtifactContract, &resp.Diagnostics)
+	if artifact != nil {
+		plan.ArtifactHash = types.StringValue(artifact.hash)
+		plan.BytecodeHash = types.StringValue(artifact.bytecodeHash)
+		plan.Abi = types.StringValue(artifact.abiJson)
+		signatures, diags := types.ListValueFrom(ctx, types.StringType, artifact.signatures)
+		resp.Diagnostics.Append(diags...)
+		plan.AbiSignatures = signatures
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !req.State.Raw.IsNull() {
+		var state contractModel
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		// Imported contract doesn't have the bytecode hash until the artifact is stored
+		if !state.BytecodeHash.IsNull() && !plan.BytecodeHash.Equal(state.BytecodeHash) {
+			resp.RequiresReplace.Append(path.Root("bytecode_hash"))
+		}
+		if len(resp.RequiresReplace) == 0 {
+			plan.keepDeployed(ctx, state, &resp.Diagnostics)
+			// Code of the imported contract is checked against the artifact before it's stored
+			if state.BytecodeHash.IsNull() && artifact != nil && isInitCodeKnown(plan) && r.client != nil {
+				r.setImportedCodeHash(ctx, &plan, artifact, resp.Private, &resp.Diagnostics)
+			}
+			resp.Diagnostics.Append(resp.Plan.Set(ctx, plan)...)
+			return
+		}
+	}
+
+	if artifact != nil && !plan.Salt.IsNull() {
+		plan.Address = plan.create2Address(ctx, artifact, &resp.Diagnostics)
+	} else if plan.Salt.IsNull() && (plan.PredictAddress.ValueBool() || !plan.ExpectedNonce.IsNull()) {
+		r.predictAddress(ctx, &plan, &resp.Diagnostics)
+	}
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, plan)...)
 }
 
-func (*contractResource) Delete(context.Context, resource.DeleteRequest, *resource.DeleteResponse) {
+// predictAddress sets the address of the contract deployed with the expected or the pending nonce of the
+// signer, along with the nonce and the sender. Address stays unknown until the signer is known.
+func (r *contractResource) predictAddress(ctx context.Context, plan *contractModel, diags *diag.Diagnostics) {
+	config := plan.signerConfig()
+	if r.client == nil || plan.ExpectedNonce.IsUnknown() || config.Signer.IsUnknown() || config.Keystore.IsUnknown() || config.KeystorePassword.IsUnknown() ||
+		config.SignerName.IsUnknown() || config.From.IsUnknown() && config.Signer.IsNull() && config.Keystore.IsNull() && config.SignerName.IsNull() {
+		return
+	}
+	signer := config.resolve(r.providerData, diags)
+	if signer == nil {
+		return
+	}
+
+	nonce, err := r.client.PendingNonceAt(ctx, signer.address)
+	if err != nil {
+		diags.AddError("Cannot predict contract address", fmt.Sprintf("Cannot retrieve nonce of %s: %v", signer.address.Hex(), err))
+		return
+	}
+	if !plan.ExpectedNonce.IsNull() {
+		expected := uint64(plan.ExpectedNonce.ValueInt64())
+		if nonce > expected {
+			diags.AddAttributeError(path.Root("expected_nonce"), "Expected nonce already used", expectedNonceUsed(signer.address, nonce, expected))
+			return
+		}
+		nonce = expected
+	}
+	plan.Address = types.StringValue(crypto.CreateAddress(signer.address, nonce).Hex())
+	plan.Nonce = types.Int64Value(int64(nonce))
+	plan.From = types.StringValue(signer.address.Hex())
 }
 
-type contractModel struct {
-	Artifact        types.String `tfsdk:"artifact"`
-	Signer          types.String `tfsdk:"signer"`
-	Address         types.String `tfsdk:"address"`
-	ConstructorArgs types.List   `tfsdk:"constructor_args"`
+func expectedNonceUsed(signer common.Address, nonce uint64, expected uint64) string {
+	return fmt.Sprintf("Next nonce of %s is %d, so the contract can't be deployed with nonce %d anymore. The signer has to be replaced, or `expected_nonce` has to be updated, which changes the address of the contract", signer.Hex(), nonce, expected)
 }
 
-func (r *contractResource) deployContract(ctx context.Context, plan *tfsdk.Plan,
-	state *tfsdk.State, respDiags *diag.Diagnostics) {
+// alignNonce checks that the next nonce of the signer is the expected one, filling the gap with zero-value
+// transfers of the signer to itself when it's allowed. Fees of the transfers are the same as the fees of the
+// deployment, except for the gas limit.
+func (r *contractResource) alignNonce(ctx context.Context, model contractModel, signer *txSigner, opts *bind.TransactOpts,
+	chainID *big.Int, fees txFees, diags *diag.Diagnostics) {
+	expected := uint64(model.ExpectedNonce.ValueInt64())
+	nonce, err := r.client.PendingNonceAt(ctx, signer.address)
+	if err != nil {
+		diags.AddError("Cannot retrieve nonce", fmt.Sprintf("Cannot retrieve nonce of %s: %v", signer.address.Hex(), err))
+		return
+	}
+	switch {
+	case nonce > expected:
+		diags.AddAttributeError(path.Root("expected_nonce"), "Expected nonce already used", expectedNonceUsed(signer.address, nonce, expected))
+		return
+	case nonce < expected && !model.FillNonceGap.ValueBool():
+		diags.AddAttributeError(
+			path.Root("expected_nonce"),
+			"Nonce gap",
+			fmt.Sprintf("Next nonce of %s is %d, while the contract is expected to be deployed with nonce %d. Set `fill_nonce_gap` to send %d zero-value transfers of the signer to itself first", signer.address.Hex(), nonce, expected, expected-nonce),
+		)
+		return
+	}
+
+	transferOpts := *opts
+	transferOpts.Value = nil
+	fees.gasLimit = 0
+	var txHash common.Hash
+	for ; nonce < expected; nonce++ {
+		txHash, err = r.sendTransaction(ctx, signer, &transferOpts, chainID, fees, buildTransfer(ctx, r.client, signer.address))
+		if err != nil {
+			utils.ParseNodeError(signer.address.Hex(), nil, err, diags)
+			if !diags.HasError() {
+				diags.AddError("Transfer error", fmt.Sprintf("Signer %s\nCannot fill nonce gap: %v", signer.address.Hex(), err))
+			}
+			return
+		}
+		tflog.Info(ctx, fmt.Sprintf("Filled nonce %d of %s with transfer %s", nonce, signer.address.Hex(), txHash.Hex()))
+	}
+	if txHash != (common.Hash{}) {
+		if _, err := waitMined(ctx, r.client, txHash); err != nil {
+			diags.AddError("Error while waiting for transaction to be mined", err.Error())
+		}
+	}
+}
+
+// errNonceMoved is returned when the deployment can't be sent with the nonce the address was predicted from.
+var errNonceMoved = errors.New("nonce of the signer moved since the plan")
+
+// checkPredictedNonce checks that the deployment is sent with the planned nonce, which is known only when
+// the address is predicted. Lower nonce means the nonce cached by the provider is behind the chain, so
+// it's reported as too low for the nonce to be read from the chain again.
+func checkPredictedNonce(planned types.Int64, nonce uint64) error {
+	if planned.IsNull() || planned.IsUnknown() {
+		return nil
+	}
+	switch expected := uint64(planned.ValueInt64()); {
+	case nonce < expected:
+		return fmt.Errorf("%w: nonce %d is behind the predicted nonce %d", core.ErrNonceTooLow, nonce, expected)
+	case nonce > expected:
+		return fmt.Errorf("%w: address was predicted for nonce %d, while the next nonce of the signer is %d as other transactions of the signer were sent", errNonceMoved, expected, nonce)
+	}
+	return nil
+}
+
+// setCodeHash sets hash of the code deployed at the contract address.
+func (m *contractModel) setCodeHash(hash common.Hash) {
+	m.DeployedCodeHash = types.StringValue(hash.Hex())
+	m.CurrentCodeHash = types.StringValue(hash.Hex())
+}
+
+// keepDeployed sets attributes of the deployed contract from the state.
+func (m *contractModel) keepDeployed(ctx context.Context, state contractModel, diags *diag.Diagnostics) {
+	m.Address = state.Address
+	m.TxId = state.TxId
+	// Hash of the imported contract is calculated without immutable references of the artifact
+	if !state.BytecodeHash.IsNull() {
+		m.DeployedCodeHash = state.DeployedCodeHash
+		m.CurrentCodeHash = state.CurrentCodeHash
+	}
+	m.BlockNumber = state.BlockNumber
+	m.BlockHash = state.BlockHash
+	m.GasUsed = state.GasUsed
+	m.EffectiveGasPrice = state.EffectiveGasPrice
+	m.FeePaidWei = state.FeePaidWei
+	m.Nonce = state.Nonce
+	m.Events = state.Events
+	if m.From.IsUnknown() {
+		m.From = state.From
+	}
+	if !m.Capture.IsUnknown() {
+		m.Captured = captureEvents(ctx, state.TxId.ValueString(), state.Events, parseCapture(ctx, m.Capture, nil, diags), diags)
+	}
+}
+
+// Update only stores the attributes which don't affect the deployed contract, e.g. the signer.
+func (r *contractResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
 	var model contractModel
 
-	diags := plan.Get(ctx, &model)
-	respDiags.Append(diags...)
-	if respDiags.HasError() {
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	bytecode, err := utils.GetBytecode(model.Artifact.ValueString())
+	if model.DeployedCodeHash.IsUnknown() {
+		artifact := loadArtifact(model.Artifact, model.ArtifactPath, model.ArtifactFormat, model.ArtifactContract, &resp.Diagnostics)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		r.setImportedCodeHash(ctx, &model, artifact, resp.Private, &resp.Diagnostics)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, model)...)
+}
+
+func (*contractResource) Delete(context.Context, resource.DeleteRequest, *resource.DeleteResponse) {
+}
+
+// setImportedCodeHash sets hash of the code of the imported contract calculated with immutable references
+// of the artifact, which is not known on import. The code is expected to match the artifact, while a mismatch
+// is only an error when the artifact lists immutable references.
+func (r *contractResource) setImportedCodeHash(ctx context.Context, model *contractModel, artifact *contractArtifact, private privateState, diags *diag.Diagnostics) {
+	address := common.HexToAddress(model.Address.ValueString())
+	code, err := r.client.CodeAt(ctx, address, nil)
 	if err != nil {
-		respDiags.AddError("Error parsing bytecode", err.Error())
+		diags.AddError("Cannot retrieve deployed code", err.Error())
 		return
 	}
+	model.setCodeHash(checkArtifactCode(ctx, model.Libraries, artifact, address, code, diags))
+	setImmutableReferences(ctx, private, artifact.ImmutableReferences, diags)
+}
 
-	argTypes, err := utils.GetConstructorArgTypes(model.Artifact.ValueString())
+// ImportState imports the contract by address. Creation transaction is looked up on chain, while
+// the artifact and constructor args are stored from the configuration on the next apply without
+// deploying the contract again.
+func (r *contractResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	if !common.IsHexAddress(req.ID) {
+		resp.Diagnostics.AddError("Invalid import ID", fmt.Sprintf("Expected contract address, got '%s'", req.ID))
+		return
+	}
+	address := common.HexToAddress(req.ID)
+
+	code, err := r.client.CodeAt(ctx, address, nil)
 	if err != nil {
-		respDiags.AddError("Error parsing constructor args", err.Error())
+		resp.Diagnostics.AddError("Cannot retrieve deployed code", err.Error())
+		return
+	}
+	if len(code) == 0 {
+		resp.Diagnostics.AddError("Cannot import contract", fmt.Sprintf("There is no code at %s", address.Hex()))
 		return
 	}
 
-	abiJson, err := utils.GetAbi(model.Artifact.ValueString())
+	model := contractModel{
+		Address:         types.StringValue(address.Hex()),
+		ConstructorArgs: types.ListNull(types.StringType),
+		Libraries:       types.MapNull(types.StringType),
+		AbiSignatures:   types.ListNull(types.StringType),
+		Events:          types.ListNull(eventType),
+		Capture:         types.MapNull(types.StringType),
+		Captured:        types.MapNull(types.StringType),
+	}
+	model.setCodeHash(utils.CodeHash(code, nil))
+
+	tx, receipt, err := findCreation(ctx, r.client, address)
+	if err == nil {
+		var sender common.Address
+		sender, err = txSender(ctx, r.client, tx)
+		if err == nil {
+			model.TxId = types.StringValue(tx.Hash().Hex())
+			model.setReceipt(newTxReceipt(receipt, tx.Nonce(), sender))
+		}
+	}
 	if err != nil {
-		respDiags.AddError("Error parsing abi", err.Error())
+		resp.Diagnostics.AddWarning("Cannot find contract creation transaction", fmt.Sprintf("Creation details of %s are not imported: %v", address.Hex(), err))
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, model)...)
+}
+
+// UpgradeState upgrades the state of version 0, which stored only the artifact content, with
+// the attributes describing the artifact.
+func (*contractResource) UpgradeState(context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		0: {StateUpgrader: upgradeContractStateV0},
+	}
+}
+
+func upgradeContractStateV0(_ context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+	var state map[string]interface{}
+	if err := json.Unmarshal(req.RawState.JSON, &state); err != nil {
+		resp.Diagnostics.AddError("Cannot upgrade contract state", err.Error())
 		return
 	}
 
-	chainID, err := r.client.ChainID(ctx)
+	state["artifact_path"] = nil
+	state["artifact_format"] = nil
+	state["artifact_contract"] = nil
+	state["libraries"] = nil
+	state["salt"] = nil
+	state["create2_factory"] = nil
+	state["predict_address"] = nil
+	state["expected_nonce"] = nil
+	state["fill_nonce_gap"] = nil
+	state["artifact_hash"] = nil
+	state["bytecode_hash"] = nil
+	state["abi"] = nil
+	state["abi_signatures"] = nil
+	state["current_code_hash"] = state["deployed_code_hash"]
+	// Artifact is missing in the state of the imported contract
+	if artifactJson, ok := state["artifact"].(string); ok {
+		artifact, err := newContractArtifact(artifactJson, utils.ArtifactFormatHardhat, "")
+		if err != nil {
+			resp.Diagnostics.AddError("Cannot upgrade contract state", fmt.Sprintf("Invalid artifact: %v", err))
+			return
+		}
+		state["artifact_hash"] = artifact.hash
+		state["bytecode_hash"] = artifact.bytecodeHash
+		state["abi"] = artifact.abiJson
+		state["abi_signatures"] = artifact.signatures
+	}
+
+	upgraded, err := json.Marshal(state)
 	if err != nil {
-		respDiags.AddError("Cannot retrieve chain ID", err.Error())
+		resp.Diagnostics.AddError("Cannot upgrade contract state", err.Error())
 		return
 	}
+	resp.DynamicValue = &tfprotov6.DynamicValue{JSON: upgraded}
+}
+
+type contractModel struct {
+	Artifact             types.String  `tfsdk:"artifact"`
+	ArtifactPath         types.String  `tfsdk:"artifact_path"`
+	ArtifactFormat       types.String  `tfsdk:"artifact_format"`
+	ArtifactContract     types.String  `tfsdk:"artifact_contract"`
+	ArtifactHash         types.String  `tfsdk:"artifact_hash"`
+	BytecodeHash         types.String  `tfsdk:"bytecode_hash"`
+	Abi                  types.String  `tfsdk:"abi"`
+	AbiSignatures        types.List    `tfsdk:"abi_signatures"`
+	Signer               types.String  `tfsdk:"signer"`
+	Keystore             types.String  `tfsdk:"keystore"`
+	KeystorePassword     types.String  `tfsdk:"keystore_password"`
+	SignerName           types.String  `tfsdk:"signer_name"`
+	From                 types.String  `tfsdk:"from"`
+	Impersonate          types.String  `tfsdk:"impersonate"`
+	GasLimit             types.Int64   `tfsdk:"gas_limit"`
+	GasLimitMultiplier   types.Float64 `tfsdk:"gas_limit_multiplier"`
+	MaxFeePerGas         types.String  `tfsdk:"max_fee_per_gas"`
+	MaxPriorityFeePerGas types.String  `tfsdk:"max_priority_fee_per_gas"`
+	GasPrice             types.String  `tfsdk:"gas_price"`
+	PredictAddress       types.Bool    `tfsdk:"predict_address"`
+	ExpectedNonce        types.Int64   `tfsdk:"expected_nonce"`
+	FillNonceGap         types.Bool    `tfsdk:"fill_nonce_gap"`
+	Value                types.String  `tfsdk:"value"`
+	Address              types.String  `tfsdk:"address"`
+	ConstructorArgs      types.List    `tfsdk:"constructor_args"`
+	Libraries            types.Map     `tfsdk:"libraries"`
+	Salt                 types.String  `tfsdk:"salt"`
+	Create2Factory       types.String  `tfsdk:"create2_factory"`
+	TxId                 types.String  `tfsdk:"tx_id"`
+	DeployedCodeHash     types.String  `tfsdk:"deployed_code_hash"`
+	CurrentCodeHash      types.String  `tfsdk:"current_code_hash"`
+	BlockNumber          types.Int64   `tfsdk:"block_number"`
+	BlockHash            types.String  `tfsdk:"block_hash"`
+	GasUsed              types.Int64   `tfsdk:"gas_used"`
+	EffectiveGasPrice    types.String  `tfsdk:"effective_gas_price"`
+	FeePaidWei           types.String  `tfsdk:"fee_paid_wei"`
+	Nonce                types.Int64   `tfsdk:"nonce"`
+	Events               types.List    `tfsdk:"events"`
+	Capture              types.Map     `tfsdk:"capture"`
+	Captured             types.Map     `tfsdk:"captured"`
+}
+
+func (m contractModel) signerConfig() signerConfig {
+	return signerConfig{m.Signer, m.Keystore, m.KeystorePassword, m.SignerName, m.From, m.Impersonate}
+}
+
+func (m *contractModel) setReceipt(receipt txReceipt) {
+	m.BlockNumber = receipt.BlockNumber
+	m.BlockHash = receipt.BlockHash
+	m.GasUsed = receipt.GasUsed
+	m.EffectiveGasPrice = receipt.EffectiveGasPrice
+	m.FeePaidWei = receipt.FeePaidWei
+	m.Nonce = receipt.Nonce
+	m.From = receipt.From
+}
+
+func (m contractModel) feeConfig() feeConfig {
+	return feeConfig{m.GasLimit, m.GasLimitMultiplier, m.MaxFeePerGas, m.MaxPriorityFeePerGas, m.GasPrice}
+}
+
+func constructorArgsChanged(ctx context.Context, req planmodifier.ListRequest, resp *listplanmodifier.RequiresReplaceIfFuncResponse) {
+	resp.RequiresReplace = !isImported(ctx, req.State, &resp.Diagnostics)
+}
+
+func librariesChanged(ctx context.Context, req planmodifier.MapRequest, resp *mapplanmodifier.RequiresReplaceIfFuncResponse) {
+	resp.RequiresReplace = !isImported(ctx, req.State, &resp.Diagnostics)
+}
 
-	privateKey, err := crypto.HexToECDSA(model.Signer.ValueString())
+func create2Changed(ctx context.Context, req planmodifier.StringRequest, resp *stringplanmodifier.RequiresReplaceIfFuncResponse) {
+	resp.RequiresReplace = !isImported(ctx, req.State, &resp.Diagnostics)
+}
+
+func expectedNonceChanged(ctx context.Context, req planmodifier.Int64Request, resp *int64planmodifier.RequiresReplaceIfFuncResponse) {
+	resp.RequiresReplace = !isImported(ctx, req.State, &resp.Diagnostics)
+}
+
+func valueChanged(ctx context.Context, req planmodifier.StringRequest, resp *stringplanmodifier.RequiresReplaceIfFuncResponse) {
+	resp.RequiresReplace = !isImported(ctx, req.State, &resp.Diagnostics)
+}
+
+// isImported reports whether the contract is imported and its deployment inputs are not stored yet.
+func isImported(ctx context.Context, state tfsdk.State, diags *diag.Diagnostics) bool {
+	var bytecodeHash types.String
+	diags.Append(state.GetAttribute(ctx, path.Root("bytecode_hash"), &bytecodeHash)...)
+	return bytecodeHash.IsNull()
+}
+
+// privateState is the private state of the resource, which is only accessible through the request and response fields.
+type privateState interface {
+	GetKey(ctx context.Context, key string) ([]byte, diag.Diagnostics)
+	SetKey(ctx context.Context, key string, value []byte) diag.Diagnostics
+}
+
+// immutableReferencesKey is the private state key of the immutable references the deployed code hash is calculated with.
+const immutableReferencesKey = "immutable_references"
+
+// setImmutableReferences stores the immutable references the deployed code hash is calculated with, so that the code
+// is hashed the same way on refresh after the artifact changes.
+func setImmutableReferences(ctx context.Context, private privateState, references []utils.CodeRange, diags *diag.Diagnostics) {
+	if references == nil {
+		references = []utils.CodeRange{}
+	}
+	value, err := json.Marshal(references)
 	if err != nil {
-		respDiags.AddError("Error decoding signer to private key", err.Error())
+		diags.AddError("Cannot store immutable references", err.Error())
 		return
 	}
+	diags.Append(private.SetKey(ctx, immutableReferencesKey, value)...)
+}
+
+// immutableReferences returns immutable references stored with the deployed code hash. State without them falls back
+// to the references of the artifact stored in the state or found at the artifact path, as long as the artifact is not changed.
+func (m contractModel) immutableReferences(ctx context.Context, private privateState, diags *diag.Diagnostics) []utils.CodeRange {
+	value, getDiags := private.GetKey(ctx, immutableReferencesKey)
+	diags.Append(getDiags...)
+	if len(value) > 0 {
+		var references []utils.CodeRange
+		if err := json.Unmarshal(value, &references); err != nil {
+			diags.AddError("Cannot read immutable references", err.Error())
+		}
+		return references
+	}
+
+	var artifactDiags diag.Diagnostics
+	if artifact := loadArtifact(m.Artifact, m.ArtifactPath, m.ArtifactFormat, m.ArtifactContract, &artifactDiags); artifact != nil && artifact.hash == m.ArtifactHash.ValueString() {
+		return artifact.ImmutableReferences
+	}
+	return nil
+}
+
+// initCode returns the bytecode with linked libraries followed by the encoded constructor args.
+func (m contractModel) initCode(ctx context.Context, artifact *contractArtifact, diags *diag.Diagnostics) []byte {
+	return buildInitCode(ctx, artifact, m.Libraries, m.ConstructorArgs, diags)
+}
+
+// buildInitCode links the libraries into the artifact bytecode and appends the constructor args encoded
+// according to the artifact ABI.
+func buildInitCode(ctx context.Context, artifact *contractArtifact, libraryAddresses types.Map, constructorArgs types.List, diags *diag.Diagnostics) []byte {
+	libraries := parseLibraries(ctx, libraryAddresses, artifact, diags)
+	if diags.HasError() {
+		return nil
+	}
+	bytecode, err := artifact.LinkBytecode(libraries)
+	if err != nil {
+		diags.AddAttributeError(path.Root("libraries"), "Cannot link libraries", err.Error())
+		return nil
+	}
+
+	argTypes, err := artifact.ConstructorArgTypes()
+	if err != nil {
+		diags.AddError("Error parsing constructor args", err.Error())
+		return nil
+	}
+	args, argsDiags := utils.ParseArguments(ctx, argTypes, constructorArgs)
+	diags.Append(argsDiags...)
+	if diags.HasError() {
+		return nil
+	}
+	constructorInput, err := artifact.abi.Pack("", args...)
+	if err != nil {
+		diags.AddError("Error encoding constructor args", err.Error())
+		return nil
+	}
+	return append(bytecode, constructorInput...)
+}
+
+// create2Address returns address of the contract deployed with CREATE2, which is unknown until the salt,
+// the libraries and the constructor args are known.
+func (m contractModel) create2Address(ctx context.Context, artifact *contractArtifact, diags *diag.Diagnostics) types.String {
+	salt, ok := parseSalt(m.Salt, diags)
+	if !ok || m.Create2Factory.IsUnknown() || !isInitCodeKnown(m) {
+		return types.StringUnknown()
+	}
+	factory := parseCreate2Factory(m.Create2Factory, diags)
+	initCode := m.initCode(ctx, artifact, diags)
+	if diags.HasError() {
+		return types.StringUnknown()
+	}
+	return types.StringValue(create2Address(factory, salt, initCode).Hex())
+}
+
+func isInitCodeKnown(model contractModel) bool {
+	if model.Libraries.IsUnknown() || model.ConstructorArgs.IsUnknown() {
+		return false
+	}
+	for _, address := range model.Libraries.Elements() {
+		if address.IsUnknown() {
+			return false
+		}
+	}
+	for _, arg := range model.ConstructorArgs.Elements() {
+		if arg.IsUnknown() {
+			return false
+		}
+	}
+	return true
+}
 
-	signerAddress, err := utils.PrivateKeyToAddressString(privateKey)
+// isCreate2Deployed checks whether the contract is already deployed at the CREATE2 address, e.g. on another
+// run or by another deployer, in which case it is not deployed again. The address is derived from the
+// init code, so any code at the address is taken for the contract.
+func (r *contractResource) isCreate2Deployed(ctx context.Context, model *contractModel, artifact *contractArtifact,
+	address common.Address, factory common.Address, diags *diag.Diagnostics) bool {
+	code, err := r.client.CodeAt(ctx, address, nil)
+	if err != nil {
+		diags.AddError("Cannot retrieve deployed code", err.Error())
+		return false
+	}
+	if len(code) == 0 {
+		factoryCode, err := r.client.CodeAt(ctx, factory, nil)
+		if err != nil {
+			diags.AddError("Cannot retrieve deployed code", err.Error())
+		} else if len(factoryCode) == 0 {
+			diags.AddAttributeError(
+				path.Root("create2_factory"),
+				"CREATE2 factory not deployed",
+				fmt.Sprintf("There is no code at CREATE2 factory %s", factory.Hex()),
+			)
+		}
+		return false
+	}
+
+	// Address is derived from the init code, so any code at the address is the contract. Runtime code is only
+	// compared when the values of immutable variables can be masked
+	codeHash := utils.CodeHash(code, nil)
+	if len(artifact.ImmutableReferences) > 0 {
+		codeHash = checkArtifactCode(ctx, model.Libraries, artifact, address, code, diags)
+		if diags.HasError() {
+			return false
+		}
+	}
+	tflog.Info(ctx, fmt.Sprintf("Contract is already deployed at %s, skipping deployment", address.Hex()))
+	model.setCodeHash(codeHash)
+	return true
+}
+
+// checkArtifactCode returns hash of the code at the address, reporting an error when the code is not the runtime
+// bytecode of the artifact with the linked libraries. Code is not checked when the artifact has no runtime bytecode.
+// Values of immutable variables are only masked when the artifact lists them, otherwise a mismatch is reported
+// as a warning, as it may be caused by the constructor args.
+func checkArtifactCode(ctx context.Context, libraryAddresses types.Map, artifact *contractArtifact, address common.Address,
+	code []byte, diags *diag.Diagnostics) common.Hash {
+	codeHash := utils.CodeHash(code, artifact.ImmutableReferences)
+	if len(artifact.DeployedBytecode) == 0 {
+		return codeHash
+	}
+
+	libraries := parseLibraries(ctx, libraryAddresses, artifact, diags)
+	if diags.HasError() {
+		return codeHash
+	}
+	deployedBytecode, err := artifact.LinkDeployedBytecode(libraries)
 	if err != nil {
-		respDiags.AddError("Error calculating signer address", err.Error())
+		diags.AddError("Cannot link runtime bytecode", err.Error())
+		return codeHash
+	}
+	artifactHash := utils.CodeHash(deployedBytecode, artifact.ImmutableReferences)
+	switch {
+	case artifactHash == codeHash:
+	case len(artifact.ImmutableReferences) > 0:
+		diags.AddError(
+			"Deployed code differs",
+			fmt.Sprintf("Code at %s has hash %s, while the runtime bytecode of the artifact has hash %s", address.Hex(), codeHash.Hex(), artifactHash.Hex()),
+		)
+	default:
+		diags.AddWarning(
+			"Deployed code may differ",
+			fmt.Sprintf("Code at %s has hash %s, while the runtime bytecode of the artifact has hash %s. The artifact doesn't list immutable variables, whose values may differ", address.Hex(), codeHash.Hex(), artifactHash.Hex()),
+		)
+	}
+	return codeHash
+}
+
+func (r *contractResource) deployContract(ctx context.Context, plan *tfsdk.Plan,
+	state *tfsdk.State, private privateState, respDiags *diag.Diagnostics) {
+	var model contractModel
+
+	diags := plan.Get(ctx, &model)
+	respDiags.Append(diags...)
+	if respDiags.HasError() {
 		return
 	}
 
-	auth, err := bind.NewKeyedTransactorWithChainID(privateKey, chainID)
+	artifact := loadArtifact(model.Artifact, model.ArtifactPath, model.ArtifactFormat, model.ArtifactContract, respDiags)
+	if respDiags.HasError() {
+		return
+	}
+	if artifact == nil {
+		respDiags.AddError("Missing artifact", "Either `artifact` or `artifact_path` is required")
+		return
+	}
+	if artifact.hash != model.ArtifactHash.ValueString() {
+		respDiags.AddAttributeError(
+			path.Root("artifact_path"),
+			"Artifact changed",
+			fmt.Sprintf("Artifact has hash %s, while the plan was created with hash %s. Plan the changes again", artifact.hash, model.ArtifactHash.ValueString()),
+		)
+		return
+	}
+	parsedABI := artifact.abi
+	initCode := model.initCode(ctx, artifact, respDiags)
+	if respDiags.HasError() {
+		return
+	}
+
+	chainID, err := r.client.ChainID(ctx)
 	if err != nil {
-		respDiags.AddError("Error creating signer", err.Error())
+		respDiags.AddError("Cannot retrieve chain ID", err.Error())
 		return
 	}
 
-	parsedABI, err := abi.JSON(strings.NewReader(abiJson))
+	signer := model.signerConfig().resolve(r.providerData, respDiags)
+	if respDiags.HasError() {
+		return
+	}
+	signerAddress := signer.address.Hex()
+
+	auth, err := signer.transactOpts(ctx, chainID)
 	if err != nil {
-		diags.AddError("Unexpected error on parsing ABI", err.Error())
+		respDiags.AddError("Error creating signer", err.Error())
 		return
 	}
 
-	args, diags := utils.ParseArguments(ctx, argTypes, model.ConstructorArgs)
-	respDiags.Append(diags...)
+	fees := model.feeConfig().withDefaults(r.fees).resolve(respDiags)
+	auth.Value = parseValue(model.Value, respDiags)
 	if respDiags.HasError() {
 		return
 	}
 
-	address, tx, err := func() (common.Address, *ethTypes.Transaction, error) {
-		for {
-			address, tx, _, err := bind.DeployContract(auth, parsedABI, bytecode, r.client, args...)
-			if err != nil && (err.Error() == txpool.ErrReplaceUnderpriced.Error() || strings.HasPrefix(err.Error(), core.ErrNonceTooLow.Error())) {
-				tflog.Info(ctx,
-					fmt.Sprintf("Got error '%v' from the node, retrying", err),
-				)
-				time.Sleep(1 * time.Second)
-				continue
-			}
-			return address, tx, err
+	if !model.ExpectedNonce.IsNull() {
+		r.alignNonce(ctx, model, signer, auth, chainID, fees, respDiags)
+		if respDiags.HasError() {
+			return
 		}
-	}()
+	}
 
-	if err != nil {
-		utils.ParseNodeError(signerAddress, err, respDiags)
+	call := txCall{
+		msg:    ethereum.CallMsg{From: signer.address, Value: auth.Value, Data: initCode},
+		target: "new contract",
+		method: "constructor",
+		abis:   []*abi.ABI{&parsedABI},
+	}
+
+	deploy := buildDeploy(r.client, initCode)
+	build := func(opts *bind.TransactOpts) (*ethTypes.Transaction, error) {
+		if err := checkPredictedNonce(model.Nonce, opts.Nonce.Uint64()); err != nil {
+			return nil, err
+		}
+		return deploy(opts)
+	}
+	// address is set to the address of the contract deployed by the CREATE2 factory
+	var address common.Address
+	if salt, ok := parseSalt(model.Salt, respDiags); ok {
+		factory := parseCreate2Factory(model.Create2Factory, respDiags)
+		address = create2Address(factory, salt, initCode)
+		if r.isCreate2Deployed(ctx, &model, artifact, address, factory, respDiags) {
+			model.Address = types.StringValue(address.Hex())
+			model.TxId = types.StringNull()
+			model.setReceipt(txReceipt{From: types.StringValue(signerAddress)})
+			model.Events, model.Captured = decodeEvents(ctx, &ethTypes.Receipt{}, call.abis, parseCapture(ctx, model.Capture, call.abis, respDiags), respDiags)
+			respDiags.Append(state.Set(ctx, model)...)
+			setImmutableReferences(ctx, private, artifact.ImmutableReferences, respDiags)
+			return
+		}
 		if respDiags.HasError() {
 			return
 		}
 
-		respDiags.AddError(
-			"Deploy error",
-			fmt.Sprintf("Signer %s\n%v", signerAddress, err),
-		)
-		return
+		call.msg.To = &factory
+		call.msg.Data = append(salt.Bytes(), initCode...)
+		call.target = fmt.Sprintf("CREATE2 factory %s", factory.Hex())
+		factoryContract := bind.NewBoundContract(factory, abi.ABI{}, r.client, r.client, r.client)
+		build = func(opts *bind.TransactOpts) (*ethTypes.Transaction, error) {
+			return factoryContract.RawTransact(opts, call.msg.Data)
+		}
 	}
 
-	// Wait until transaction is mined
-	_, err = bind.WaitMined(ctx, r.client, tx)
-	if err != nil {
-		respDiags.AddError("Error while waiting for transaction to be mined", err.Error())
+	deployed := r.deploy(ctx, signer, auth, chainID, fees, call, build, address, func(err error) bool {
+		if errors.Is(err, errNonceMoved) && !model.ExpectedNonce.IsNull() {
+			respDiags.AddAttributeError(path.Root("expected_nonce"), "Expected nonce already used", fmt.Sprintf("Signer %s\n%v", signerAddress, err))
+			return true
+		}
+		if errors.Is(err, errNonceMoved) {
+			respDiags.AddAttributeError(
+				path.Root("address"),
+				"Predicted address changed",
+				fmt.Sprintf("Signer %s\nContract would be deployed at another address than %s, %v. Plan the changes again", signerAddress, model.Address.ValueString(), err),
+			)
+			return true
+		}
+		return false
+	}, respDiags)
+	if respDiags.HasError() {
 		return
 	}
 
-	model.Address = types.StringValue(address.String())
+	// Contract is recorded at the address it landed at, so it's not lost when the prediction is wrong
+	if model.Salt.IsNull() && !model.Address.IsUnknown() && model.Address.ValueString() != deployed.address.Hex() {
+		respDiags.AddAttributeError(
+			path.Root("address"),
+			"Contract deployed at unexpected address",
+			fmt.Sprintf("Signer %s\nTransaction %s deployed the contract at %s, while the address %s was predicted", signerAddress, deployed.txHash.Hex(), deployed.address.Hex(), model.Address.ValueString()),
+		)
+	}
+
+	model.Address = types.StringValue(deployed.address.Hex())
+	model.TxId = types.StringValue(deployed.txHash.String())
+	model.setCodeHash(utils.CodeHash(deployed.code, artifact.ImmutableReferences))
+	model.setReceipt(newTxReceipt(deployed.receipt, auth.Nonce.Uint64(), signer.address))
+	// Failed capture is reported after the transaction is mined, so the state is saved anyway
+	model.Events, model.Captured = decodeEvents(ctx, deployed.receipt, call.abis, parseCapture(ctx, model.Capture, call.abis, respDiags), respDiags)
 
 	respDiags.Append(state.Set(ctx, model)...)
+	setImmutableReferences(ctx, private, artifact.ImmutableReferences, respDiags)
 }
@@ -1,10 +1,28 @@
 package provider
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
 	"regexp"
+	"strings"
 	"testing"
 
+	"terraform-provider-evm/internal/utils"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/crypto"
+	res "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestAccResourceContract(t *testing.T) {
@@ -24,3 +42,914 @@ func TestAccResourceContract(t *testing.T) {
 		},
 	})
 }
+
+func TestAccResourceContractKeystore(t *testing.T) {
+	keystorePath := writeFaucetKeystore(t, "secret")
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `resource "evm_contract" "keystore" {
+					artifact = file("./testdata/Token.json")
+					keystore = "` + keystorePath + `"
+					keystore_password = "wrong"
+					constructor_args=["Name","SYM", 1000000000 * pow(10, 18), 18]
+				}`,
+				ExpectError: regexp.MustCompile(`Wrong keystore password`),
+			},
+			{
+				Config: `resource "evm_contract" "keystore" {
+					artifact = file("./testdata/Token.json")
+					keystore = file("` + keystorePath + `")
+					keystore_password = "secret"
+					constructor_args=["Name","SYM", 1000000000 * pow(10, 18), 18]
+				}`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr("evm_contract.keystore", "address", regexp.MustCompile(`0x[A-Fa-f0-9]{20}`)),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourceContractRemoteSigner(t *testing.T) {
+	signerUrl := startRemoteSigner(t, faucetPk)
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `provider "evm" {
+					signers {
+						name = "web3signer"
+						remote_url = "` + signerUrl + `"
+						address = "` + faucetAddr.Hex() + `"
+					}
+					signers {
+						name = "clef"
+						remote_url = "` + signerUrl + `"
+						remote_method = "account_signTransaction"
+						address = "` + faucetAddr.Hex() + `"
+					}
+				}
+
+				resource "evm_contract" "remote" {
+					artifact = file("./testdata/Token.json")
+					signer_name = "web3signer"
+					constructor_args=["Name","SYM", 1000000000 * pow(10, 18), 18]
+				}
+
+				resource "evm_contract_tx" "remote_transfer" {
+					address = evm_contract.remote.address
+					signer_name = "clef"
+					method = "transfer(address,uint256)"
+					args=["0x000000000000000000000000000000000000dead", 10 * pow(10, 18)]
+				}`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr("evm_contract.remote", "address", regexp.MustCompile(`0x[A-Fa-f0-9]{20}`)),
+					resource.TestMatchResourceAttr("evm_contract_tx.remote_transfer", "tx_id", regexp.MustCompile(`0x[A-Fa-f0-9]{20}`)),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourceContractEmptyCode(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				// Constructor stops without returning runtime code
+				Config: `resource "evm_contract" "empty" {
+					artifact = jsonencode({ abi = [], bytecode = "0x00" })
+					signer = "` + faucetPk + `"
+				}`,
+				ExpectError: regexp.MustCompile("there is no code at 0x"),
+			},
+			{
+				// Constructor reverts in mined transaction
+				Config: `resource "evm_contract" "reverted" {
+					artifact = jsonencode({ abi = [], bytecode = "0x60006000fd" })
+					signer = "` + faucetPk + `"
+					gas_limit = 100000
+				}`,
+				ExpectError: regexp.MustCompile(`(?s)Transaction failed.*Method constructor.*Reason reverted without a reason`),
+			},
+		},
+	})
+}
+
+// testCheckCodeDrift checks that the code at the contract address differs from the deployed code.
+func testCheckCodeDrift(name string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		attributes := s.RootModule().Resources[name].Primary.Attributes
+		if attributes["current_code_hash"] == attributes["deployed_code_hash"] {
+			return fmt.Errorf("expected %s deployed code hash to differ from the current code hash %s", name, attributes["current_code_hash"])
+		}
+		return nil
+	}
+}
+
+func TestAccResourceContractDrift(t *testing.T) {
+	config := `resource "evm_contract" "drift" {
+		artifact = file("./testdata/Token.json")
+		signer = "` + faucetPk + `"
+		constructor_args = ["Name", "SYM", 1000 * pow(10, 18), 18]
+	}`
+
+	var address string
+	setCode := func(code string) {
+		if err := testClient.CallContext(context.Background(), nil, "anvil_setCode", common.HexToAddress(address), code); err != nil {
+			t.Fatalf("Cannot set code: %v", err)
+		}
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr("evm_contract.drift", "deployed_code_hash", regexp.MustCompile(`^0x[a-f0-9]{64}$`)),
+					resource.TestCheckResourceAttrPair("evm_contract.drift", "current_code_hash", "evm_contract.drift", "deployed_code_hash"),
+					func(s *terraform.State) error {
+						address = s.RootModule().Resources["evm_contract.drift"].Primary.Attributes["address"]
+						return nil
+					},
+				),
+			},
+			{
+				// Code replaced outside of Terraform is reported on refresh
+				PreConfig:    func() { setCode("0x6001") },
+				RefreshState: true,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("evm_contract.drift", "current_code_hash", crypto.Keccak256Hash(hexutil.MustDecode("0x6001")).Hex()),
+					testCheckCodeDrift("evm_contract.drift"),
+				),
+			},
+			{
+				// Deployed code hash is kept, so the change is reported again
+				RefreshState: true,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("evm_contract.drift", "current_code_hash", crypto.Keccak256Hash(hexutil.MustDecode("0x6001")).Hex()),
+					testCheckCodeDrift("evm_contract.drift"),
+				),
+			},
+			{
+				// Contract without code is removed from the state and deployed again
+				PreConfig: func() { setCode("0x") },
+				Config:    config,
+				Check: func(s *terraform.State) error {
+					if redeployed := s.RootModule().Resources["evm_contract.drift"].Primary.Attributes["address"]; redeployed == address {
+						return fmt.Errorf("contract is not redeployed to the new address")
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
+
+func TestAccResourceContractDriftImmutable(t *testing.T) {
+	artifact, err := os.ReadFile("./testdata/out/Immutable.sol/Immutable.json")
+	if err != nil {
+		t.Fatalf("Cannot read artifact: %v", err)
+	}
+	artifactPath := filepath.Join(t.TempDir(), "Immutable.json")
+	if err := os.WriteFile(artifactPath, artifact, 0600); err != nil {
+		t.Fatalf("Cannot write artifact: %v", err)
+	}
+
+	config := `resource "evm_contract" "immutable" {
+		artifact_path = "` + artifactPath + `"
+		signer = "` + faucetPk + `"
+		constructor_args = [42]
+	}`
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check:  resource.TestCheckResourceAttrPair("evm_contract.immutable", "current_code_hash", "evm_contract.immutable", "deployed_code_hash"),
+			},
+			{
+				// Code is hashed with the immutable references it was deployed with after the artifact changes
+				PreConfig: func() {
+					extended := strings.Replace(string(artifact), `"abi": [`, `"abi": [{"type": "event", "name": "Paused", "inputs": [], "anonymous": false},`, 1)
+					if err := os.WriteFile(artifactPath, []byte(extended), 0600); err != nil {
+						t.Fatalf("Cannot write artifact: %v", err)
+					}
+				},
+				RefreshState:       true,
+				ExpectNonEmptyPlan: true,
+				Check:              resource.TestCheckResourceAttrPair("evm_contract.immutable", "current_code_hash", "evm_contract.immutable", "deployed_code_hash"),
+			},
+		},
+	})
+}
+
+func TestAccResourceContractReplace(t *testing.T) {
+	artifact, err := os.ReadFile("./testdata/Token.json")
+	if err != nil {
+		t.Fatalf("Cannot read artifact: %v", err)
+	}
+	// Recompiled artifact differs only by the IPFS hash of the metadata
+	recompiledPath := filepath.Join(t.TempDir(), "Token.json")
+	recompiled := strings.Replace(string(artifact), "94bea0b667d9388bfd5641", "0000000000000000000000", 1)
+	if err := os.WriteFile(recompiledPath, []byte(recompiled), 0600); err != nil {
+		t.Fatalf("Cannot write artifact: %v", err)
+	}
+
+	config := func(artifactPath string, signer string, supply int) string {
+		return fmt.Sprintf(`resource "evm_contract" "replace" {
+			artifact = file("%s")
+			%s
+			constructor_args = ["Name", "SYM", %d, 18]
+		}`, artifactPath, signer, supply)
+	}
+
+	var address string
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config("./testdata/Token.json", `signer = "`+faucetPk+`"`, 1000),
+				Check: func(s *terraform.State) error {
+					address = s.RootModule().Resources["evm_contract.replace"].Primary.Attributes["address"]
+					return nil
+				},
+			},
+			{
+				// Signer change is only stored in the state
+				Config: config("./testdata/Token.json", `from = "`+faucetAddr.Hex()+`"`, 1000),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{plancheck.ExpectResourceAction("evm_contract.replace", plancheck.ResourceActionUpdate)},
+				},
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPtr("evm_contract.replace", "address", &address),
+					resource.TestCheckResourceAttr("evm_contract.replace", "from", faucetAddr.Hex()),
+				),
+			},
+			{
+				// Metadata change of the recompiled artifact doesn't redeploy the contract
+				Config: config(recompiledPath, `signer = "`+faucetPk+`"`, 1000),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{plancheck.ExpectResourceAction("evm_contract.replace", plancheck.ResourceActionUpdate)},
+				},
+				Check: resource.TestCheckResourceAttrPtr("evm_contract.replace", "address", &address),
+			},
+			{
+				Config: config(recompiledPath, `signer = "`+faucetPk+`"`, 2000),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{plancheck.ExpectResourceAction("evm_contract.replace", plancheck.ResourceActionReplace)},
+				},
+				Check: func(s *terraform.State) error {
+					if s.RootModule().Resources["evm_contract.replace"].Primary.Attributes["address"] == address {
+						return fmt.Errorf("contract is not redeployed")
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
+
+func TestAccResourceContractArtifactPath(t *testing.T) {
+	artifact, err := os.ReadFile("./testdata/Token.json")
+	if err != nil {
+		t.Fatalf("Cannot read artifact: %v", err)
+	}
+	// Artifact with the same bytecode and an extra event declared in the ABI
+	extendedPath := filepath.Join(t.TempDir(), "Token.json")
+	extended := strings.Replace(string(artifact), `"abi": [`, `"abi": [{"type": "event", "name": "Paused", "inputs": [], "anonymous": false},`, 1)
+	if err := os.WriteFile(extendedPath, []byte(extended), 0600); err != nil {
+		t.Fatalf("Cannot write artifact: %v", err)
+	}
+
+	config := func(artifact string) string {
+		return `resource "evm_contract" "token" {
+			` + artifact + `
+			signer = "` + faucetPk + `"
+			constructor_args = ["Name", "SYM", 1000, 18]
+		}`
+	}
+
+	var address string
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      config(`artifact = file("./testdata/Token.json")` + "\n" + `artifact_path = "./testdata/Token.json"`),
+				ExpectError: regexp.MustCompile("Invalid artifact configuration"),
+			},
+			{
+				Config:      config(`artifact_path = "./testdata/Missing.json"`),
+				ExpectError: regexp.MustCompile("Cannot read artifact"),
+			},
+			{
+				Config: config(`artifact = file("./testdata/Token.json")`),
+				Check: func(s *terraform.State) error {
+					address = s.RootModule().Resources["evm_contract.token"].Primary.Attributes["address"]
+					return nil
+				},
+			},
+			{
+				// Moving the artifact to a file doesn't redeploy the contract
+				Config: config(`artifact_path = "./testdata/Token.json"`),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{plancheck.ExpectResourceAction("evm_contract.token", plancheck.ResourceActionUpdate)},
+				},
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPtr("evm_contract.token", "address", &address),
+					resource.TestCheckNoResourceAttr("evm_contract.token", "artifact"),
+					resource.TestMatchResourceAttr("evm_contract.token", "artifact_hash", regexp.MustCompile(`^[0-9a-f]{64}$`)),
+					resource.TestMatchResourceAttr("evm_contract.token", "bytecode_hash", regexp.MustCompile(`^0x[0-9a-f]{64}$`)),
+					resource.TestCheckTypeSetElemAttr("evm_contract.token", "abi_signatures.*", "function transfer(address to, uint256 amount) returns(bool)"),
+				),
+			},
+			{
+				// ABI change is shown by the signatures
+				Config: config(`artifact_path = "` + extendedPath + `"`),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{plancheck.ExpectResourceAction("evm_contract.token", plancheck.ResourceActionUpdate)},
+				},
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPtr("evm_contract.token", "address", &address),
+					resource.TestCheckTypeSetElemAttr("evm_contract.token", "abi_signatures.*", "event Paused()"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourceContractArtifactFormat(t *testing.T) {
+	artifact, err := os.ReadFile("./testdata/Token.json")
+	if err != nil {
+		t.Fatalf("Cannot read artifact: %v", err)
+	}
+	var hardhat struct {
+		Abi              json.RawMessage `json:"abi"`
+		Bytecode         string          `json:"bytecode"`
+		DeployedBytecode string          `json:"deployedBytecode"`
+	}
+	if err := json.Unmarshal(artifact, &hardhat); err != nil {
+		t.Fatalf("Cannot parse artifact: %v", err)
+	}
+	foundry, err := json.Marshal(map[string]interface{}{
+		"abi":              hardhat.Abi,
+		"bytecode":         map[string]interface{}{"object": hardhat.Bytecode, "linkReferences": map[string]interface{}{}},
+		"deployedBytecode": map[string]interface{}{"object": hardhat.DeployedBytecode, "linkReferences": map[string]interface{}{}},
+	})
+	if err != nil {
+		t.Fatalf("Cannot encode artifact: %v", err)
+	}
+	foundryPath := filepath.Join(t.TempDir(), "Token.json")
+	if err := os.WriteFile(foundryPath, foundry, 0600); err != nil {
+		t.Fatalf("Cannot write artifact: %v", err)
+	}
+
+	config := func(format string) string {
+		return `resource "evm_contract" "token" {
+			artifact_path = "` + foundryPath + `"
+			` + format + `
+			signer = "` + faucetPk + `"
+			constructor_args = ["Name", "SYM", 1000, 18]
+		}`
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      config(`artifact_format = "brownie"`),
+				ExpectError: regexp.MustCompile("Invalid artifact format"),
+			},
+			{
+				Config:      config(`artifact_format = "hardhat"`),
+				ExpectError: regexp.MustCompile("invalid hardhat artifact"),
+			},
+			{
+				Config: config(""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr("evm_contract.token", "address", regexp.MustCompile(`^0x[A-Fa-f0-9]{40}$`)),
+					resource.TestCheckResourceAttr("evm_contract.token", "bytecode_hash", crypto.Keccak256Hash(utils.MaskCode(hexutil.MustDecode(hardhat.Bytecode), nil)).Hex()),
+				),
+			},
+			{
+				Config: config(`artifact_format = "foundry"`),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{plancheck.ExpectResourceAction("evm_contract.token", plancheck.ResourceActionUpdate)},
+				},
+			},
+		},
+	})
+}
+
+func TestAccResourceContractArtifactContract(t *testing.T) {
+	token, err := os.ReadFile("./testdata/Token.json")
+	if err != nil {
+		t.Fatalf("Cannot read artifact: %v", err)
+	}
+	expected, err := newContractArtifact(string(token), "", "")
+	if err != nil {
+		t.Fatalf("Cannot parse artifact: %v", err)
+	}
+	config := func(contract string) string {
+		return `resource "evm_contract" "token" {
+			artifact_path = "./testdata/combined.json"
+			` + contract + `
+			signer = "` + faucetPk + `"
+			constructor_args = ["Name", "SYM", 1000, 18]
+		}`
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      config(""),
+				ExpectError: regexp.MustCompile(`expected a single contract with\s+bytecode, found 2`),
+			},
+			{
+				Config:      config(`artifact_contract = "contracts/IToken.sol:IToken"`),
+				ExpectError: regexp.MustCompile(`contract\s+contracts/IToken.sol:IToken\s+with\s+bytecode`),
+			},
+			{
+				Config: config(`artifact_contract = "contracts/Token.sol:Token"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr("evm_contract.token", "address", regexp.MustCompile(`^0x[A-Fa-f0-9]{40}$`)),
+					resource.TestCheckResourceAttr("evm_contract.token", "bytecode_hash", expected.bytecodeHash),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourceContractLibraries(t *testing.T) {
+	config := func(libraries string) string {
+		return `resource "evm_contract" "math" {
+			artifact = file("./testdata/Token.json")
+			signer = "` + faucetPk + `"
+			constructor_args = ["Math", "MTH", 1000, 18]
+		}
+
+		resource "evm_contract" "linked" {
+			artifact_path = "./testdata/Linked.json"
+			signer = "` + faucetPk + `"
+			libraries = ` + libraries + `
+		}`
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      config(`{}`),
+				ExpectError: regexp.MustCompile("missing library contracts/Math.sol:Math"),
+			},
+			{
+				Config:      config(`{"contracts/Math.sol:Math" = evm_contract.math.address, "contracts/Vector.sol:Vector" = evm_contract.math.address}`),
+				ExpectError: regexp.MustCompile(`unused library contracts/Vector.sol:Vector, referenced libraries are\s+\[contracts/Math.sol:Math\]`),
+			},
+			{
+				Config:      config(`{"contracts/Math.sol:Math" = "0x1234"}`),
+				ExpectError: regexp.MustCompile("Invalid library address"),
+			},
+			{
+				Config: config(`{"contracts/Math.sol:Math" = evm_contract.math.address}`),
+				Check: func(s *terraform.State) error {
+					// Runtime code of the linked contract is the library address
+					library := common.HexToAddress(s.RootModule().Resources["evm_contract.math"].Primary.Attributes["address"])
+					return resource.TestCheckResourceAttr("evm_contract.linked", "deployed_code_hash", utils.CodeHash(library.Bytes(), nil).Hex())(s)
+				},
+			},
+			{
+				Config: config(`{"contracts/Math.sol:Math" = "0x000000000000000000000000000000000000dEaD"}`),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{plancheck.ExpectResourceAction("evm_contract.linked", plancheck.ResourceActionReplace)},
+				},
+				Check: resource.TestCheckResourceAttr("evm_contract.linked", "deployed_code_hash",
+					utils.CodeHash(common.HexToAddress("0x000000000000000000000000000000000000dEaD").Bytes(), nil).Hex()),
+			},
+		},
+	})
+}
+
+func TestAccResourceContractCreate2(t *testing.T) {
+	factory := `resource "evm_contract" "factory" {
+		artifact_path = "./testdata/Create2Factory.json"
+		signer = "` + faucetPk + `"
+	}
+	`
+	config := func(name string, salt string, factory string) string {
+		return `resource "evm_contract" "` + name + `" {
+			artifact_path = "./testdata/Token.json"
+			signer = "` + faucetPk + `"
+			constructor_args = ["Name", "SYM", 1000, 18]
+			salt = "` + salt + `"
+			create2_factory = ` + factory + `
+		}
+		`
+	}
+
+	var address string
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      factory + config("token", "0xzz", "evm_contract.factory.address"),
+				ExpectError: regexp.MustCompile("Invalid salt"),
+			},
+			{
+				Config: factory + `resource "evm_contract" "token" {
+					artifact_path = "./testdata/Token.json"
+					signer = "` + faucetPk + `"
+					constructor_args = ["Name", "SYM", 1000, 18]
+					create2_factory = evm_contract.factory.address
+				}`,
+				ExpectError: regexp.MustCompile("Missing salt"),
+			},
+			{
+				Config:      factory + config("token", "0x01", `"0x000000000000000000000000000000000000dEaD"`),
+				ExpectError: regexp.MustCompile("CREATE2 factory not deployed"),
+			},
+			{
+				Config: factory + config("token", "0x01", "evm_contract.factory.address"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("evm_contract.token", "tx_id"),
+					func(s *terraform.State) error {
+						address = s.RootModule().Resources["evm_contract.token"].Primary.Attributes["address"]
+						return nil
+					},
+				),
+			},
+			{
+				// Address is known at plan time and the deployment is skipped as the contract is already deployed
+				Config: factory + config("token", "0x01", "evm_contract.factory.address") + config("copy", "0x0000000000000000000000000000000000000000000000000000000000000001", "evm_contract.factory.address"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{expectPlannedValue{"evm_contract.copy", "address", &address}},
+				},
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPtr("evm_contract.copy", "address", &address),
+					resource.TestCheckResourceAttrPair("evm_contract.copy", "deployed_code_hash", "evm_contract.token", "deployed_code_hash"),
+					resource.TestCheckNoResourceAttr("evm_contract.copy", "tx_id"),
+				),
+			},
+			{
+				// Address is derived from the init code, so any code at the address is taken for the contract
+				// when the artifact doesn't list immutable variables
+				PreConfig: func() {
+					if err := testClient.CallContext(context.Background(), nil, "anvil_setCode", common.HexToAddress(address), "0x6001"); err != nil {
+						t.Fatalf("Cannot set code: %v", err)
+					}
+				},
+				Config: factory + config("token", "0x01", "evm_contract.factory.address") + config("copy", "0x01", "evm_contract.factory.address"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("evm_contract.copy", "deployed_code_hash", utils.CodeHash(common.FromHex("0x6001"), nil).Hex()),
+					resource.TestCheckNoResourceAttr("evm_contract.copy", "tx_id"),
+				),
+			},
+			{
+				Config: factory + config("token", "0x02", "evm_contract.factory.address"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{plancheck.ExpectResourceAction("evm_contract.token", plancheck.ResourceActionReplace)},
+				},
+				Check: func(s *terraform.State) error {
+					if s.RootModule().Resources["evm_contract.token"].Primary.Attributes["address"] == address {
+						return fmt.Errorf("contract is not deployed at the new address")
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
+
+func TestAccResourceContractCreate2Immutable(t *testing.T) {
+	config := func(name string, artifactPath string) string {
+		return `resource "evm_contract" "` + name + `" {
+			artifact_path = "` + artifactPath + `"
+			signer = "` + faucetPk + `"
+			constructor_args = [42]
+			salt = "0x01"
+			create2_factory = evm_contract.factory.address
+		}
+		`
+	}
+	factory := `resource "evm_contract" "factory" {
+		artifact_path = "./testdata/Create2Factory.json"
+		signer = "` + faucetPk + `"
+	}
+	` + config("immutable", "./testdata/Immutable.json")
+
+	var address string
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: factory,
+				Check: func(s *terraform.State) error {
+					address = s.RootModule().Resources["evm_contract.immutable"].Primary.Attributes["address"]
+					return nil
+				},
+			},
+			{
+				// Immutable value differs from the artifact runtime bytecode, which doesn't list immutable variables
+				Config: factory + config("copy", "./testdata/Immutable.json"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPtr("evm_contract.copy", "address", &address),
+					resource.TestCheckResourceAttrPair("evm_contract.copy", "deployed_code_hash", "evm_contract.immutable", "deployed_code_hash"),
+					resource.TestCheckNoResourceAttr("evm_contract.copy", "tx_id"),
+				),
+			},
+			{
+				// Immutable value is masked with the references of the Foundry artifact
+				Config: factory + config("foundry", "./testdata/out/Immutable.sol/Immutable.json"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPtr("evm_contract.foundry", "address", &address),
+					resource.TestCheckNoResourceAttr("evm_contract.foundry", "tx_id"),
+				),
+			},
+			{
+				// Deployment is not skipped when the code at the address is not the artifact runtime bytecode
+				PreConfig: func() {
+					if err := testClient.CallContext(context.Background(), nil, "anvil_setCode", common.HexToAddress(address), "0x6001"); err != nil {
+						t.Fatalf("Cannot set code: %v", err)
+					}
+				},
+				Config:      factory + config("mismatch", "./testdata/out/Immutable.sol/Immutable.json"),
+				ExpectError: regexp.MustCompile("Deployed code differs"),
+			},
+		},
+	})
+}
+
+func TestAccResourceContractPredictAddress(t *testing.T) {
+	config := func(predict bool) string {
+		return fmt.Sprintf(`resource "evm_contract" "token" {
+			artifact_path = "./testdata/Token.json"
+			signer = "%s"
+			constructor_args = ["Name", "SYM", 1000, 18]
+			predict_address = %t
+		}`, faucetPk, predict)
+	}
+
+	var address string
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				PreConfig: func() {
+					nonce, err := testClient.PendingNonceAt(context.Background(), faucetAddr)
+					if err != nil {
+						t.Fatal(err)
+					}
+					address = crypto.CreateAddress(faucetAddr, nonce).Hex()
+				},
+				Config: config(true),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{expectPlannedValue{"evm_contract.token", "address", &address}},
+				},
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPtr("evm_contract.token", "address", &address),
+					resource.TestCheckResourceAttr("evm_contract.token", "from", faucetAddr.Hex()),
+				),
+			},
+			{
+				Config: config(false),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{plancheck.ExpectResourceAction("evm_contract.token", plancheck.ResourceActionUpdate)},
+				},
+				Check: resource.TestCheckResourceAttrPtr("evm_contract.token", "address", &address),
+			},
+		},
+	})
+}
+
+func TestAccResourceContractExpectedNonce(t *testing.T) {
+	config := func(name string, attributes string) string {
+		return fmt.Sprintf(`resource "evm_contract" "%s" {
+			artifact_path = "./testdata/Token.json"
+			signer = "%s"
+			constructor_args = ["Name", "SYM", 1000, 18]
+			%s
+		}
+		`, name, faucetPk, attributes)
+	}
+
+	// Nonce of the signer skips 2 transactions
+	pending, err := testClient.PendingNonceAt(context.Background(), faucetAddr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nonce := fmt.Sprint(pending + 2)
+	address := crypto.CreateAddress(faucetAddr, pending+2).Hex()
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      config("token", "expected_nonce = 1\nsalt = \"0x01\""),
+				ExpectError: regexp.MustCompile("Invalid nonce configuration"),
+			},
+			{
+				Config:      config("token", "expected_nonce = "+nonce),
+				ExpectError: regexp.MustCompile("Nonce gap"),
+			},
+			{
+				Config: config("token", "expected_nonce = "+nonce+"\nfill_nonce_gap = true"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{expectPlannedValue{"evm_contract.token", "address", &address}},
+				},
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("evm_contract.token", "address", address),
+					resource.TestCheckResourceAttr("evm_contract.token", "nonce", nonce),
+				),
+			},
+			{
+				Config:      config("token", "expected_nonce = "+nonce+"\nfill_nonce_gap = true") + config("late", "expected_nonce = 0"),
+				ExpectError: regexp.MustCompile("Expected nonce already used"),
+			},
+		},
+	})
+}
+
+func TestCheckPredictedNonce(t *testing.T) {
+	assert.NoError(t, checkPredictedNonce(types.Int64Unknown(), 3))
+	assert.NoError(t, checkPredictedNonce(types.Int64Value(3), 3))
+	assert.ErrorIs(t, checkPredictedNonce(types.Int64Value(3), 2), core.ErrNonceTooLow)
+	assert.ErrorIs(t, checkPredictedNonce(types.Int64Value(3), 4), errNonceMoved)
+}
+
+func TestUpgradeContractStateV0(t *testing.T) {
+	artifact, err := os.ReadFile("./testdata/Token.json")
+	if err != nil {
+		t.Fatalf("Cannot read artifact: %v", err)
+	}
+	rawState, err := json.Marshal(map[string]interface{}{
+		"artifact": string(artifact),
+		"address":  "0x000000000000000000000000000000000000dEaD",
+	})
+	if err != nil {
+		t.Fatalf("Cannot encode state: %v", err)
+	}
+
+	var resp res.UpgradeStateResponse
+	upgradeContractStateV0(context.Background(), res.UpgradeStateRequest{RawState: &tfprotov6.RawState{JSON: rawState}}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Cannot upgrade state: %v", resp.Diagnostics)
+	}
+
+	var state map[string]interface{}
+	if err := json.Unmarshal(resp.DynamicValue.JSON, &state); err != nil {
+		t.Fatalf("Cannot decode state: %v", err)
+	}
+	expected, err := newContractArtifact(string(artifact), "", "")
+	if err != nil {
+		t.Fatalf("Cannot parse artifact: %v", err)
+	}
+	assert.Equal(t, string(artifact), state["artifact"])
+	assert.Equal(t, "0x000000000000000000000000000000000000dEaD", state["address"])
+	assert.Nil(t, state["artifact_path"])
+	assert.Equal(t, expected.hash, state["artifact_hash"])
+	assert.Equal(t, expected.bytecodeHash, state["bytecode_hash"])
+	assert.Equal(t, expected.abiJson, state["abi"])
+	assert.Contains(t, state["abi_signatures"], "event Transfer(address indexed from, address indexed to, uint256 value)")
+}
+
+func TestAccResourceContractImport(t *testing.T) {
+	config := func(imported string) string {
+		return `resource "evm_contract" "token" {
+			artifact = file("./testdata/Token.json")
+			signer = "` + faucetPk + `"
+			constructor_args = ["Name", "SYM", 1000 * pow(10, 18), 18]
+		}
+		` + imported
+	}
+	imported := `resource "evm_contract" "imported" {
+		artifact = file("./testdata/Token.json")
+		signer = "` + faucetPk + `"
+		constructor_args = ["Name", "SYM", 1000 * pow(10, 18), 18]
+	}`
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config(""),
+			},
+			{
+				Config:                               config(""),
+				ResourceName:                         "evm_contract.token",
+				ImportState:                          true,
+				ImportStateIdFunc:                    testImportStateAttr("evm_contract.token", "address"),
+				ImportStateVerify:                    true,
+				ImportStateVerifyIdentifierAttribute: "address",
+				ImportStateVerifyIgnore:              []string{"artifact", "artifact_hash", "bytecode_hash", "abi", "abi_signatures", "constructor_args", "signer", "events", "captured"},
+			},
+			{
+				Config:        config(imported),
+				ResourceName:  "evm_contract.imported",
+				ImportState:   true,
+				ImportStateId: "0x000000000000000000000000000000000000dEaD",
+				ExpectError:   regexp.MustCompile("There is no code at"),
+			},
+			{
+				Config:             config(imported),
+				ResourceName:       "evm_contract.imported",
+				ImportState:        true,
+				ImportStatePersist: true,
+				ImportStateIdFunc:  testImportStateAttr("evm_contract.token", "address"),
+			},
+			{
+				// Imported contract only stores the artifact and constructor args
+				Config: config(imported),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("evm_contract.imported", plancheck.ResourceActionUpdate),
+						expectKnownPlan{"evm_contract.imported"},
+					},
+					PostApplyPostRefresh: []plancheck.PlanCheck{plancheck.ExpectEmptyPlan()},
+				},
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair("evm_contract.imported", "address", "evm_contract.token", "address"),
+					resource.TestCheckResourceAttrPair("evm_contract.imported", "deployed_code_hash", "evm_contract.token", "deployed_code_hash"),
+				),
+			},
+			{
+				Config:   config(imported),
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestAccResourceContractImportMismatch(t *testing.T) {
+	token := `resource "evm_contract" "token" {
+		artifact = file("./testdata/Token.json")
+		signer = "` + faucetPk + `"
+		constructor_args = ["Name", "SYM", 1000 * pow(10, 18), 18]
+	}
+	`
+	imported := `resource "evm_contract" "imported" {
+		artifact_path = "./testdata/out/Immutable.sol/Immutable.json"
+		signer = "` + faucetPk + `"
+		constructor_args = [42]
+	}`
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: token,
+			},
+			{
+				Config:             token + imported,
+				ResourceName:       "evm_contract.imported",
+				ImportState:        true,
+				ImportStatePersist: true,
+				ImportStateIdFunc:  testImportStateAttr("evm_contract.token", "address"),
+			},
+			{
+				// Contract is imported with the artifact of another contract
+				Config:      token + imported,
+				ExpectError: regexp.MustCompile("Deployed code differs"),
+			},
+		},
+	})
+}
+
+func TestAccResourceContractImportImmutable(t *testing.T) {
+	config := func(name string) string {
+		return `resource "evm_contract" "` + name + `" {
+			artifact_path = "./testdata/Immutable.json"
+			signer = "` + faucetPk + `"
+			constructor_args = [42]
+		}
+		`
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config("immutable"),
+			},
+			{
+				Config:             config("immutable") + config("imported"),
+				ResourceName:       "evm_contract.imported",
+				ImportState:        true,
+				ImportStatePersist: true,
+				ImportStateIdFunc:  testImportStateAttr("evm_contract.immutable", "address"),
+			},
+			{
+				// Immutable value differs from the artifact runtime bytecode, which doesn't list immutable variables
+				Config: config("immutable") + config("imported"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair("evm_contract.imported", "address", "evm_contract.immutable", "address"),
+					resource.TestCheckResourceAttrPair("evm_contract.imported", "deployed_code_hash", "evm_contract.immutable", "deployed_code_hash"),
+				),
+			},
+		},
+	})
+}
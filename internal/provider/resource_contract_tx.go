@@ -1,19 +1,20 @@
 package provider
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"strings"
 	"terraform-provider-evm/internal/utils"
-	"time"
 
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/core"
-	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	ethTypes "github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
@@ -26,7 +27,7 @@ func NewContractTxResource() resource.Resource {
 }
 
 type contractTxResource struct {
-	client EvmClient
+	providerData
 }
 
 func (*contractTxResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -36,16 +37,11 @@ func (*contractTxResource) Metadata(_ context.Context, req resource.MetadataRequ
 func (*contractTxResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		Description: "Resource for triggering transactions on deployed smart contracts.",
-		Attributes: map[string]schema.Attribute{
+		Attributes: eventAttributes(receiptAttributes(feeAttributes(signerAttributes(map[string]schema.Attribute{
 			"address": schema.StringAttribute{
 				Description: "Blockchain address of the contract to execute transaction on (20-byte hex with `0x` prefix)",
 				Required:    true,
 			},
-			"signer": schema.StringAttribute{
-				Description: "Deploy transaction signer private key (32-byte hex, no `0x` prefix). Can reference `evm_random_pk.pk` resource",
-				Required:    true,
-				Sensitive:   true,
-			},
 			"method": schema.StringAttribute{
 				Description: "Contract function to execute, specified as a function name with comma-separated parameter types in brackets (e.g. `transfer(address,uint256)`), see the list of supported types [here](../../README.md#deployment-and-transaction-args)",
 				Required:    true,
@@ -55,11 +51,19 @@ func (*contractTxResource) Schema(_ context.Context, _ resource.SchemaRequest, r
 				ElementType: types.StringType,
 				Optional:    true,
 			},
+			"abi": schema.StringAttribute{
+				Description: "Contract ABI in JSON format, or a compiled artifact of any format supported by `evm_contract` containing it, used to decode custom errors when the transaction reverts and emitted `events`",
+				Optional:    true,
+			},
+			"value": schema.StringAttribute{
+				Description: "Amount of ETH sent with the call to the payable function, in wei or with a unit suffix (e.g. `0.1 ether` or `20 gwei`). Defaults to 0",
+				Optional:    true,
+			},
 			"tx_id": schema.StringAttribute{
 				Description: "Transaction id of submitted transaction, populated after transaction is executed.",
 				Computed:    true,
 			},
-		},
+		})))),
 	}
 }
 
@@ -69,18 +73,37 @@ func (r *contractTxResource) Configure(ctx context.Context, req resource.Configu
 		return
 	}
 
-	client, ok := req.ProviderData.(EvmClient)
+	data, ok := req.ProviderData.(providerData)
 
 	if !ok {
 		resp.Diagnostics.AddError(
-			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *ethclient.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected providerData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
 	}
 
-	r.client = client
+	r.providerData = data
+}
+
+func (*contractTxResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var model contractTxModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	model.signerConfig().validate(&resp.Diagnostics)
+	model.feeConfig().validate(&resp.Diagnostics)
+	parseValue(model.Value, &resp.Diagnostics)
+	contractAbi := parseAbi(model.Abi, &resp.Diagnostics)
+	if contractAbi != nil {
+		parseCapture(ctx, model.Capture, []*abi.ABI{contractAbi}, &resp.Diagnostics)
+	} else if !model.Capture.IsNull() && model.Abi.IsNull() {
+		resp.Diagnostics.AddAttributeError(path.Root("capture"), "Invalid capture", "`abi` declaring the events is required to capture their arguments")
+	}
 }
 
 func (r *contractTxResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -91,19 +114,298 @@ func (*contractTxResource) Read(context.Context, resource.ReadRequest, *resource
 
 }
 
+// ModifyPlan keeps the executed transaction when the call doesn't change, e.g. only the signer or the
+// textual form of the args (such as the case of addresses) is updated, or the transaction is imported.
+func (r *contractTxResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() || req.Plan.Raw.Equal(req.State.Raw) || r.client == nil {
+		return
+	}
+
+	var plan, state contractTxModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() || state.TxId.IsNull() || !isCallKnown(plan) {
+		return
+	}
+
+	// Invalid method and args are reported by apply
+	var diags diag.Diagnostics
+	contractCall := plan.contractCall(ctx, &diags)
+	value := parseValue(plan.Value, &diags)
+	if diags.HasError() {
+		return
+	}
+
+	tx, _, err := r.client.TransactionByHash(ctx, common.HexToHash(state.TxId.ValueString()))
+	if err != nil {
+		tflog.Warn(ctx, fmt.Sprintf("Cannot retrieve transaction %s: %v", state.TxId.ValueString(), err))
+		return
+	}
+	if tx.To() == nil || *tx.To() != common.HexToAddress(plan.Address.ValueString()) ||
+		!bytes.Equal(tx.Data(), contractCall.input) || tx.Value().Cmp(value) != 0 {
+		return
+	}
+
+	plan.TxId = state.TxId
+	plan.BlockNumber = state.BlockNumber
+	plan.BlockHash = state.BlockHash
+	plan.GasUsed = state.GasUsed
+	plan.EffectiveGasPrice = state.EffectiveGasPrice
+	plan.FeePaidWei = state.FeePaidWei
+	plan.Nonce = state.Nonce
+	if plan.From.IsUnknown() {
+		plan.From = state.From
+	}
+	// Events are decoded at plan time once the ABI and captured arguments are known
+	if isCaptureKnown(plan) {
+		r.decodeEvents(ctx, &plan, &resp.Diagnostics)
+	}
+
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, plan)...)
+}
+
+func isCaptureKnown(model contractTxModel) bool {
+	if model.Abi.IsUnknown() || model.Capture.IsUnknown() {
+		return false
+	}
+	for _, spec := range model.Capture.Elements() {
+		if spec.IsUnknown() {
+			return false
+		}
+	}
+	return true
+}
+
+func isCallKnown(model contractTxModel) bool {
+	if model.Address.IsUnknown() || model.Method.IsUnknown() || model.Args.IsUnknown() || model.Value.IsUnknown() {
+		return false
+	}
+	for _, arg := range model.Args.Elements() {
+		if arg.IsUnknown() {
+			return false
+		}
+	}
+	return true
+}
+
+// Update sends the transaction again when the call changes, otherwise only events are decoded again
+// as the ABI or the captured arguments may change.
 func (r *contractTxResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	r.prepareAndSendTransaction(ctx, &req.Plan, &resp.State, &resp.Diagnostics)
+	var model contractTxModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if model.TxId.IsUnknown() {
+		r.prepareAndSendTransaction(ctx, &req.Plan, &resp.State, &resp.Diagnostics)
+		return
+	}
+
+	if !r.decodeEvents(ctx, &model, &resp.Diagnostics) {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, model)...)
 }
 
 func (*contractTxResource) Delete(context.Context, resource.DeleteRequest, *resource.DeleteResponse) {
 }
 
+// ImportState imports the mined transaction by hash. Method and args are decoded for the widely used
+// standard methods, otherwise the method is set to the selector and the configuration is stored on
+// the next apply, without sending the transaction again as long as it describes the same call.
+func (r *contractTxResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	hash, err := hexutil.Decode(req.ID)
+	if err != nil || len(hash) != common.HashLength {
+		resp.Diagnostics.AddError("Invalid import ID", fmt.Sprintf("Expected transaction hash, got '%s'", req.ID))
+		return
+	}
+	txHash := common.BytesToHash(hash)
+
+	tx, isPending, err := r.client.TransactionByHash(ctx, txHash)
+	if err != nil {
+		resp.Diagnostics.AddError("Cannot retrieve transaction", err.Error())
+		return
+	}
+	if isPending || tx.To() == nil {
+		resp.Diagnostics.AddError("Cannot import transaction", fmt.Sprintf("Transaction %s is pending or deploys a contract", txHash.Hex()))
+		return
+	}
+
+	receipt, err := r.client.TransactionReceipt(ctx, txHash)
+	if err != nil {
+		resp.Diagnostics.AddError("Cannot retrieve transaction receipt", err.Error())
+		return
+	}
+	if receipt.Status != ethTypes.ReceiptStatusSuccessful {
+		resp.Diagnostics.AddError("Cannot import transaction", fmt.Sprintf("Transaction %s failed", txHash.Hex()))
+		return
+	}
+
+	sender, err := txSender(ctx, r.client, tx)
+	if err != nil {
+		resp.Diagnostics.AddError("Cannot recover transaction sender", err.Error())
+		return
+	}
+
+	model := contractTxModel{
+		Address:  types.StringValue(tx.To().Hex()),
+		Args:     types.ListNull(types.StringType),
+		TxId:     types.StringValue(txHash.Hex()),
+		Events:   types.ListNull(eventType),
+		Capture:  types.MapNull(types.StringType),
+		Captured: types.MapNull(types.StringType),
+	}
+	if tx.Value().Sign() > 0 {
+		model.Value = types.StringValue(tx.Value().String())
+	}
+	model.setReceipt(newTxReceipt(receipt, tx.Nonce(), sender))
+
+	model.Method, model.Args = decodeMethod(ctx, tx.Data(), &resp.Diagnostics)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, model)...)
+}
+
+// decodeEvents decodes events of the executed transaction with the ABI of the model. It returns false
+// when the transaction receipt can't be retrieved, while failed captures are only reported.
+func (r *contractTxResource) decodeEvents(ctx context.Context, model *contractTxModel, diags *diag.Diagnostics) bool {
+	receipt, err := r.client.TransactionReceipt(ctx, common.HexToHash(model.TxId.ValueString()))
+	if err != nil {
+		diags.AddError("Cannot retrieve transaction receipt", err.Error())
+		return false
+	}
+
+	var abis []*abi.ABI
+	if contractAbi := parseAbi(model.Abi, diags); contractAbi != nil {
+		abis = []*abi.ABI{contractAbi}
+	}
+	model.Events, model.Captured = decodeEvents(ctx, receipt, abis, parseCapture(ctx, model.Capture, abis, diags), diags)
+	return true
+}
+
+// decodeMethod decodes call of the known standard method, otherwise returns the selector as the method.
+func decodeMethod(ctx context.Context, input []byte, diags *diag.Diagnostics) (types.String, types.List) {
+	selector := input
+	if len(selector) > 4 {
+		selector = selector[:4]
+	}
+	method, ok := utils.MethodBySelector(selector)
+	if !ok {
+		diags.AddWarning("Unknown method", fmt.Sprintf("Method with selector %s is not recognized, specify it with args in the configuration", hexutil.Encode(selector)))
+		return types.StringValue(hexutil.Encode(selector)), types.ListNull(types.StringType)
+	}
+
+	methodName, argTypes, err := utils.ExtractNameAndTypes(ctx, method)
+	if err != nil {
+		diags.AddError("Unexpected error on parsing method signature", err.Error())
+		return types.StringValue(method), types.ListNull(types.StringType)
+	}
+	fakeABI, err := utils.GenerateFakeABI(ctx, methodName, argTypes)
+	if err != nil {
+		diags.AddError("Unexpected error on parsing method signature", err.Error())
+		return types.StringValue(method), types.ListNull(types.StringType)
+	}
+	values, err := fakeABI.Methods[methodName].Inputs.Unpack(input[4:])
+	if err != nil {
+		diags.AddWarning("Cannot decode method args", fmt.Sprintf("Args of %s: %v", method, err))
+		return types.StringValue(method), types.ListNull(types.StringType)
+	}
+	if len(values) == 0 {
+		return types.StringValue(method), types.ListNull(types.StringType)
+	}
+
+	args, argsDiags := types.ListValueFrom(ctx, types.StringType, utils.FormatArguments(values))
+	diags.Append(argsDiags...)
+	return types.StringValue(method), args
+}
+
 type contractTxModel struct {
-	Signer  types.String `tfsdk:"signer"`
-	Address types.String `tfsdk:"address"`
-	Method  types.String `tfsdk:"method"`
-	Args    types.List   `tfsdk:"args"`
-	TxId    types.String `tfsdk:"tx_id"`
+	Signer               types.String  `tfsdk:"signer"`
+	Keystore             types.String  `tfsdk:"keystore"`
+	KeystorePassword     types.String  `tfsdk:"keystore_password"`
+	SignerName           types.String  `tfsdk:"signer_name"`
+	From                 types.String  `tfsdk:"from"`
+	Impersonate          types.String  `tfsdk:"impersonate"`
+	GasLimit             types.Int64   `tfsdk:"gas_limit"`
+	GasLimitMultiplier   types.Float64 `tfsdk:"gas_limit_multiplier"`
+	MaxFeePerGas         types.String  `tfsdk:"max_fee_per_gas"`
+	MaxPriorityFeePerGas types.String  `tfsdk:"max_priority_fee_per_gas"`
+	GasPrice             types.String  `tfsdk:"gas_price"`
+	Value                types.String  `tfsdk:"value"`
+	Address              types.String  `tfsdk:"address"`
+	Method               types.String  `tfsdk:"method"`
+	Args                 types.List    `tfsdk:"args"`
+	Abi                  types.String  `tfsdk:"abi"`
+	TxId                 types.String  `tfsdk:"tx_id"`
+	BlockNumber          types.Int64   `tfsdk:"block_number"`
+	BlockHash            types.String  `tfsdk:"block_hash"`
+	GasUsed              types.Int64   `tfsdk:"gas_used"`
+	EffectiveGasPrice    types.String  `tfsdk:"effective_gas_price"`
+	FeePaidWei           types.String  `tfsdk:"fee_paid_wei"`
+	Nonce                types.Int64   `tfsdk:"nonce"`
+	Events               types.List    `tfsdk:"events"`
+	Capture              types.Map     `tfsdk:"capture"`
+	Captured             types.Map     `tfsdk:"captured"`
+}
+
+func (m contractTxModel) signerConfig() signerConfig {
+	return signerConfig{m.Signer, m.Keystore, m.KeystorePassword, m.SignerName, m.From, m.Impersonate}
+}
+
+func (m *contractTxModel) setReceipt(receipt txReceipt) {
+	m.BlockNumber = receipt.BlockNumber
+	m.BlockHash = receipt.BlockHash
+	m.GasUsed = receipt.GasUsed
+	m.EffectiveGasPrice = receipt.EffectiveGasPrice
+	m.FeePaidWei = receipt.FeePaidWei
+	m.Nonce = receipt.Nonce
+	m.From = receipt.From
+}
+
+func (m contractTxModel) feeConfig() feeConfig {
+	return feeConfig{m.GasLimit, m.GasLimitMultiplier, m.MaxFeePerGas, m.MaxPriorityFeePerGas, m.GasPrice}
+}
+
+// contractCall is the contract function call described by the method and args attributes.
+type contractCall struct {
+	abi    abi.ABI
+	method string
+	args   []interface{}
+	input  []byte
+}
+
+func (m contractTxModel) contractCall(ctx context.Context, diags *diag.Diagnostics) *contractCall {
+	return newContractCall(ctx, m.Method, m.Args, diags)
+}
+
+// newContractCall encodes the call of the method given by the signature in transfer(address,uint256) format.
+func newContractCall(ctx context.Context, method types.String, argValues types.List, diags *diag.Diagnostics) *contractCall {
+	methodName, expectedTypes, err := utils.ExtractNameAndTypes(ctx, method.ValueString())
+	if err != nil {
+		diags.AddError("Unexpected error on parsing method signature", err.Error())
+		return nil
+	}
+
+	fakeABI, err := utils.GenerateFakeABI(ctx, methodName, expectedTypes)
+	if err != nil {
+		diags.AddError("Unexpected error on parsing method signature", err.Error())
+		return nil
+	}
+
+	args, parseDiags := utils.ParseArguments(ctx, expectedTypes, argValues)
+	diags.Append(parseDiags...)
+	if diags.HasError() {
+		return nil
+	}
+
+	input, err := fakeABI.Pack(methodName, args...)
+	if err != nil {
+		diags.AddError("Error encoding method args", err.Error())
+		return nil
+	}
+	return &contractCall{fakeABI, methodName, args, input}
 }
 
 func (r *contractTxResource) prepareAndSendTransaction(ctx context.Context, plan *tfsdk.Plan,
@@ -123,68 +425,58 @@ func (r *contractTxResource) prepareAndSendTransaction(ctx context.Context, plan
 		return
 	}
 
-	privateKey, err := crypto.HexToECDSA(model.Signer.ValueString())
-	if err != nil {
-		respDiags.AddError("Error decoding signer to private key", err.Error())
-		return
-	}
-
-	signerAddress, err := utils.PrivateKeyToAddressString(privateKey)
-	if err != nil {
-		respDiags.AddError("Error calculating signer address", err.Error())
+	signer := model.signerConfig().resolve(r.providerData, respDiags)
+	if respDiags.HasError() {
 		return
 	}
+	signerAddress := signer.address.Hex()
 
-	auth, err := bind.NewKeyedTransactorWithChainID(privateKey, chainID)
+	auth, err := signer.transactOpts(ctx, chainID)
 	if err != nil {
 		respDiags.AddError("Error creating signer", err.Error())
 		return
 	}
 
-	// Method signature in transfer(address,uint256) format
-	methodSignature := model.Method.ValueString()
-	methodName, expectedTypes, err := utils.ExtractNameAndTypes(ctx, methodSignature)
-	if err != nil {
-		respDiags.AddError("Unexpected error on parsing method signature", err.Error())
-		return
-	}
-
-	fakeABI, err := utils.GenerateFakeABI(ctx, methodName, expectedTypes)
-	if err != nil {
-		respDiags.AddError("Unexpected error on parsing method signature", err.Error())
+	fees := model.feeConfig().withDefaults(r.fees).resolve(respDiags)
+	auth.Value = parseValue(model.Value, respDiags)
+	if respDiags.HasError() {
 		return
 	}
 
-	args, parseDiags := utils.ParseArguments(ctx, expectedTypes, model.Args)
-	respDiags.Append(parseDiags...)
+	contractCall := model.contractCall(ctx, respDiags)
 	if respDiags.HasError() {
 		return
 	}
 
 	contractAddress := common.HexToAddress(model.Address.ValueString())
+	methodSignature := model.Method.ValueString()
 
-	c := bind.NewBoundContract(contractAddress, fakeABI, r.client, r.client, r.client)
-
-	tx, err := func() (*ethTypes.Transaction, error) {
-		for {
-			tx, err := c.Transact(auth, methodName, args...)
-			if err != nil && (err.Error() == txpool.ErrReplaceUnderpriced.Error() || strings.HasPrefix(err.Error(), core.ErrNonceTooLow.Error())) {
-				tflog.Info(ctx,
-					fmt.Sprintf("Got error '%v' from the node, retrying", err),
-				)
-				time.Sleep(1 * time.Second)
-				continue
-			}
-			return tx, err
-		}
-	}()
+	c := bind.NewBoundContract(contractAddress, contractCall.abi, r.client, r.client, r.client)
+
+	call := txCall{
+		msg:    ethereum.CallMsg{From: signer.address, To: &contractAddress, Value: auth.Value, Data: contractCall.input},
+		target: contractAddress.Hex(),
+		method: methodSignature,
+	}
+	if contractAbi := parseAbi(model.Abi, respDiags); contractAbi != nil {
+		call.abis = []*abi.ABI{contractAbi}
+	}
+
+	txHash, err := r.sendTransaction(ctx, signer, auth, chainID, fees, func(opts *bind.TransactOpts) (*ethTypes.Transaction, error) {
+		return c.Transact(opts, contractCall.method, contractCall.args...)
+	})
 
 	if err != nil {
-		utils.ParseNodeError(signerAddress, err, respDiags)
+		utils.ParseNodeError(signerAddress, auth.Value, err, respDiags)
 		if respDiags.HasError() {
 			return
 		}
 
+		if reason, ok := call.revertReason(ctx, r.client, err); ok {
+			respDiags.AddError("Transaction reverted", call.details(reason))
+			return
+		}
+
 		respDiags.AddError(
 			"Transaction error",
 			fmt.Sprintf("Signer %s\n%v", signerAddress, err),
@@ -193,13 +485,46 @@ func (r *contractTxResource) prepareAndSendTransaction(ctx context.Context, plan
 	}
 
 	// Wait until transaction is mined
-	_, err = bind.WaitMined(ctx, r.client, tx)
+	receipt, err := waitMined(ctx, r.client, txHash)
 	if err != nil {
 		respDiags.AddError("Error while waiting for transaction to be mined", err.Error())
 		return
 	}
 
-	model.TxId = types.StringValue(tx.Hash().String())
+	call.checkReceipt(ctx, r.client, receipt, respDiags)
+	if respDiags.HasError() {
+		return
+	}
+
+	model.TxId = types.StringValue(txHash.String())
+	model.setReceipt(newTxReceipt(receipt, auth.Nonce.Uint64(), signer.address))
+	// Failed capture is reported after the transaction is mined, so the state is saved anyway
+	model.Events, model.Captured = decodeEvents(ctx, receipt, call.abis, parseCapture(ctx, model.Capture, call.abis, respDiags), respDiags)
 
 	respDiags.Append(state.Set(ctx, model)...)
 }
+
+// parseAbi parses ABI used to decode custom errors and events, which is either ABI JSON or an artifact of
+// any supported format containing it.
+func parseAbi(value types.String, diags *diag.Diagnostics) *abi.ABI {
+	if value.IsNull() || value.IsUnknown() {
+		return nil
+	}
+
+	abiJson := value.ValueString()
+	if !strings.HasPrefix(strings.TrimSpace(abiJson), "[") {
+		artifact, err := utils.ParseArtifact(abiJson, "", "")
+		if err != nil {
+			diags.AddAttributeError(path.Root("abi"), "Error parsing abi", err.Error())
+			return nil
+		}
+		abiJson = artifact.Abi
+	}
+
+	parsedABI, err := abi.JSON(strings.NewReader(abiJson))
+	if err != nil {
+		diags.AddAttributeError(path.Root("abi"), "Error parsing abi", err.Error())
+		return nil
+	}
+	return &parsedABI
+}
@@ -1,10 +1,17 @@
 package provider
 
 import (
+	"fmt"
+	"math/big"
 	"regexp"
 	"testing"
 
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
 )
 
 func TestAccResourceContractTx(t *testing.T) {
@@ -27,8 +34,407 @@ func TestAccResourceContractTx(t *testing.T) {
 				`,
 				Check: resource.ComposeTestCheckFunc(
 					resource.TestMatchResourceAttr("evm_contract_tx.token_transfer", "tx_id", regexp.MustCompile(`0x[A-Fa-f0-9]{20}`)),
+					resource.TestCheckResourceAttr("evm_contract_tx.token_transfer", "from", faucetAddr.Hex()),
+					resource.TestCheckResourceAttrSet("evm_contract_tx.token_transfer", "gas_used"),
+					resource.TestCheckResourceAttrSet("evm_contract_tx.token_transfer", "nonce"),
+					resource.TestMatchResourceAttr("evm_contract.basic", "tx_id", regexp.MustCompile(`^0x[a-f0-9]{64}$`)),
+					resource.TestCheckResourceAttr("evm_contract.basic", "from", faucetAddr.Hex()),
+					resource.TestCheckResourceAttrSet("evm_contract.basic", "block_number"),
+					resource.TestCheckResourceAttrSet("evm_contract.basic", "fee_paid_wei"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourceContractTxParallel(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `provider "evm" {
+					retry {
+						max_attempts = 3
+						backoff = "100ms"
+					}
+				}
+
+				resource "evm_contract" "parallel" {
+					artifact = file("./testdata/Token.json")
+					signer = "` + faucetPk + `"
+					constructor_args=["Name","SYM", 1000000000 * pow(10, 18), 18]
+				}
+
+				resource "evm_contract_tx" "parallel_transfer" {
+					count = 20
+					address = evm_contract.parallel.address
+					signer = "` + faucetPk + `"
+					method = "transfer(address,uint256)"
+					args=["0x000000000000000000000000000000000000dead", (count.index + 1) * pow(10, 18)]
+				}
+				`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr("evm_contract_tx.parallel_transfer.0", "tx_id", regexp.MustCompile(`0x[A-Fa-f0-9]{20}`)),
+					resource.TestMatchResourceAttr("evm_contract_tx.parallel_transfer.19", "tx_id", regexp.MustCompile(`0x[A-Fa-f0-9]{20}`)),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourceContractTxFees(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `resource "evm_contract_tx" "conflicting_fees" {
+					address = "0x000000000000000000000000000000000000dead"
+					signer = "` + faucetPk + `"
+					method = "transfer(address,uint256)"
+					args=["0x000000000000000000000000000000000000dead", 1]
+					gas_price = "10 gwei"
+					max_fee_per_gas = "20 gwei"
+				}
+				`,
+				ExpectError: regexp.MustCompile("Invalid fee configuration"),
+			},
+			{
+				Config: `provider "evm" {
+					max_fee_per_gas = "100 gwei"
+					max_priority_fee_per_gas = "2 gwei"
+					gas_limit_multiplier = 2
+				}
+
+				resource "evm_contract" "fees" {
+					artifact = file("./testdata/Token.json")
+					signer = "` + faucetPk + `"
+					constructor_args=["Name","SYM", 1000000000 * pow(10, 18), 18]
+				}
+
+				resource "evm_contract_tx" "default_fees" {
+					address = evm_contract.fees.address
+					signer = "` + faucetPk + `"
+					method = "transfer(address,uint256)"
+					args=["0x000000000000000000000000000000000000dead", 1]
+				}
+
+				resource "evm_contract_tx" "legacy_fees" {
+					address = evm_contract.fees.address
+					signer = "` + faucetPk + `"
+					method = "transfer(address,uint256)"
+					args=["0x000000000000000000000000000000000000dead", 1]
+					gas_limit = 100000
+					gas_price = "50 gwei"
+				}
+				`,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckTransaction("evm_contract_tx.default_fees", "tx_id", func(tx *types.Transaction) error {
+						if tx.Type() != types.DynamicFeeTxType || tx.GasFeeCap().Cmp(big.NewInt(100*params.GWei)) != 0 ||
+							tx.GasTipCap().Cmp(big.NewInt(2*params.GWei)) != 0 {
+							return fmt.Errorf("unexpected fees %v/%v of type %d transaction", tx.GasFeeCap(), tx.GasTipCap(), tx.Type())
+						}
+						// Token transfer to a new holder costs around 50000 gas
+						if tx.Gas() < 60000 {
+							return fmt.Errorf("gas limit %d is not multiplied", tx.Gas())
+						}
+						return nil
+					}),
+					testCheckTransaction("evm_contract_tx.legacy_fees", "tx_id", func(tx *types.Transaction) error {
+						if tx.Type() != types.LegacyTxType || tx.GasPrice().Cmp(big.NewInt(50*params.GWei)) != 0 || tx.Gas() != 100000 {
+							return fmt.Errorf("unexpected gas %d and price %v of type %d transaction", tx.Gas(), tx.GasPrice(), tx.Type())
+						}
+						return nil
+					}),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourceContractTxValue(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `resource "evm_contract" "vault" {
+					artifact = file("./testdata/Vault.json")
+					signer = "` + faucetPk + `"
+					value = "1 bitcoin"
+				}
+				`,
+				ExpectError: regexp.MustCompile("Invalid value"),
+			},
+			{
+				Config: `resource "evm_contract" "vault" {
+					artifact = file("./testdata/Vault.json")
+					signer = "` + faucetPk + `"
+					value = "1 ether"
+				}
+
+				resource "evm_contract_tx" "deposit" {
+					address = evm_contract.vault.address
+					signer = "` + faucetPk + `"
+					method = "deposit()"
+					value = "0.5 ether"
+				}
+				`,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckBalance("evm_contract.vault", "address", big.NewInt(15*params.Ether/10)),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourceContractTxRevert(t *testing.T) {
+	// Reverter fixture reverts with the call data, so methods named after errors revert with these errors
+	config := func(method string, args string, abi string) string {
+		return `resource "evm_contract" "reverter" {
+			artifact = file("./testdata/Reverter.json")
+			signer = "` + faucetPk + `"
+		}
+
+		resource "evm_contract_tx" "revert" {
+			address = evm_contract.reverter.address
+			signer = "` + faucetPk + `"
+			method = "` + method + `"
+			args = ` + args + `
+			` + abi + `
+		}
+		`
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      config("Error(string)", `["insufficient allowance"]`, ""),
+				ExpectError: regexp.MustCompile(`(?s)Transaction reverted.*Method Error\(string\).*Reason Error\("insufficient allowance"\)`),
+			},
+			{
+				Config:      config("Panic(uint256)", `[17]`, ""),
+				ExpectError: regexp.MustCompile(`Reason Panic\(0x11\): arithmetic underflow or overflow`),
+			},
+			{
+				Config:      config("InsufficientBalance(uint256,uint256)", `[1, 2]`, `abi = file("./testdata/Reverter.json")`),
+				ExpectError: regexp.MustCompile(`Reason InsufficientBalance\(available=1, required=2\)`),
+			},
+			{
+				Config:      config("InsufficientBalance(uint256,uint256)", `[1, 2]`, ""),
+				ExpectError: regexp.MustCompile(`Reason unknown custom error 0xcf479181`),
+			},
+			{
+				// Gas limit skips estimation, so the transaction is mined and fails
+				Config:      config("Error(string)", `["mined"]`, "gas_limit = 100000"),
+				ExpectError: regexp.MustCompile(`(?s)Transaction failed.*Reason Error\("mined"\)\s+Transaction\s+0x[0-9a-f]{64}`),
+			},
+		},
+	})
+}
+
+func TestAccResourceContractTxSignerName(t *testing.T) {
+	t.Setenv("EVM_TEST_SIGNER", faucetPk)
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `provider "evm" {
+					signers {
+						name = "deployer"
+						private_key = "` + faucetPk + `"
+					}
+					signers {
+						name = "operator"
+						env = "EVM_TEST_SIGNER"
+					}
+				}
+
+				resource "evm_contract" "named" {
+					artifact = file("./testdata/Token.json")
+					signer_name = "deployer"
+					constructor_args=["Name","SYM", 1000000000 * pow(10, 18), 18]
+				}
+
+				resource "evm_contract_tx" "named_transfer" {
+					address = evm_contract.named.address
+					signer_name = "operator"
+					method = "transfer(address,uint256)"
+					args=["0x000000000000000000000000000000000000dead", 10 * pow(10, 18)]
+				}
+				`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr("evm_contract_tx.named_transfer", "tx_id", regexp.MustCompile(`0x[A-Fa-f0-9]{20}`)),
+					resource.TestCheckNoResourceAttr("evm_contract_tx.named_transfer", "signer"),
+				),
+			},
+			{
+				Config: `resource "evm_contract_tx" "unknown" {
+					address = "0x000000000000000000000000000000000000dead"
+					signer_name = "missing"
+					method = "transfer(address,uint256)"
+					args=["0x000000000000000000000000000000000000dead", 1]
+				}
+				`,
+				ExpectError: regexp.MustCompile(`Signer 'missing' is not configured`),
+			},
+		},
+	})
+}
+
+func TestAccResourceContractTxUnlockedAccount(t *testing.T) {
+	config := func(impersonate string) string {
+		return `resource "evm_contract" "unlocked" {
+			artifact = file("./testdata/Token.json")
+			from = "` + faucetAddr.Hex() + `"
+			constructor_args=["Name","SYM", 1000000000 * pow(10, 18), 18]
+		}
+
+		resource "evm_contract_tx" "impersonated" {
+			address = evm_contract.unlocked.address
+			from = "` + impersonatedAddr.Hex() + `"
+			` + impersonate + `
+			method = "approve(address,uint256)"
+			args=["0x000000000000000000000000000000000000dead", 10 * pow(10, 18)]
+		}
+		`
+	}
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      config(""),
+				ExpectError: regexp.MustCompile(`no signer for account`),
+			},
+			{
+				Config: config(`impersonate = "anvil"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr("evm_contract.unlocked", "address", regexp.MustCompile(`0x[A-Fa-f0-9]{20}`)),
+					resource.TestMatchResourceAttr("evm_contract_tx.impersonated", "tx_id", regexp.MustCompile(`0x[A-Fa-f0-9]{20}`)),
 				),
 			},
 		},
 	})
 }
+
+func TestAccResourceContractTxEvents(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `resource "evm_contract_tx" "invalid_capture" {
+					address = "0x000000000000000000000000000000000000dEaD"
+					signer = "` + faucetPk + `"
+					method = "transfer(address,uint256)"
+					args = ["0x000000000000000000000000000000000000dead", 1]
+					abi = file("./testdata/Token.json")
+					capture = {
+						to = "Transfer.recipient"
+					}
+				}
+				`,
+				ExpectError: regexp.MustCompile("Event Transfer with argument recipient is not declared"),
+			},
+			{
+				Config: `resource "evm_contract" "token" {
+					artifact = file("./testdata/Token.json")
+					signer = "` + faucetPk + `"
+					constructor_args = ["Name", "SYM", 1000 * pow(10, 18), 18]
+					capture = {
+						minted = "Transfer.value"
+					}
+				}
+
+				resource "evm_contract_tx" "transfer" {
+					address = evm_contract.token.address
+					signer = "` + faucetPk + `"
+					method = "transfer(address,uint256)"
+					args = ["0x000000000000000000000000000000000000dead", 10 * pow(10, 18)]
+					abi = file("./testdata/Token.json")
+					capture = {
+						recipient = "Transfer.to"
+					}
+				}
+				`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("evm_contract.token", "captured.minted", "1000000000000000000000"),
+					resource.TestCheckResourceAttr("evm_contract_tx.transfer", "events.#", "1"),
+					resource.TestCheckResourceAttr("evm_contract_tx.transfer", "events.0.name", "Transfer"),
+					resource.TestCheckResourceAttrPair("evm_contract_tx.transfer", "events.0.address", "evm_contract.token", "address"),
+					resource.TestCheckResourceAttr("evm_contract_tx.transfer", "events.0.args.from", faucetAddr.Hex()),
+					resource.TestCheckResourceAttr("evm_contract_tx.transfer", "events.0.args.value", "10000000000000000000"),
+					resource.TestCheckResourceAttr("evm_contract_tx.transfer", "captured.recipient", "0x000000000000000000000000000000000000dEaD"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourceContractTxImport(t *testing.T) {
+	config := func(imported string) string {
+		return `resource "evm_contract" "token" {
+			artifact = file("./testdata/Token.json")
+			signer = "` + faucetPk + `"
+			constructor_args = ["Name", "SYM", 1000 * pow(10, 18), 18]
+		}
+
+		resource "evm_contract_tx" "transfer" {
+			address = evm_contract.token.address
+			signer = "` + faucetPk + `"
+			method = "transfer(address,uint256)"
+			args = ["0x000000000000000000000000000000000000dead", 10 * pow(10, 18)]
+		}
+		` + imported
+	}
+	imported := `resource "evm_contract_tx" "imported" {
+		address = evm_contract.token.address
+		signer = "` + faucetPk + `"
+		method = "transfer(address,uint256)"
+		args = ["0x000000000000000000000000000000000000dead", 10 * pow(10, 18)]
+	}`
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config(""),
+			},
+			{
+				Config:            config(""),
+				ResourceName:      "evm_contract_tx.transfer",
+				ImportState:       true,
+				ImportStateIdFunc: testImportStateAttr("evm_contract_tx.transfer", "tx_id"),
+				ImportStateCheck: func(states []*terraform.InstanceState) error {
+					attributes := states[0].Attributes
+					if attributes["method"] != "transfer(address,uint256)" || attributes["args.0"] != "0x000000000000000000000000000000000000dEaD" ||
+						attributes["args.1"] != "10000000000000000000" || attributes["from"] != faucetAddr.Hex() {
+						return fmt.Errorf("unexpected imported attributes %v", attributes)
+					}
+					return nil
+				},
+			},
+			{
+				Config:             config(imported),
+				ResourceName:       "evm_contract_tx.imported",
+				ImportState:        true,
+				ImportStatePersist: true,
+				ImportStateIdFunc:  testImportStateAttr("evm_contract_tx.transfer", "tx_id"),
+			},
+			{
+				// Imported transaction isn't sent again, although the address case differs
+				Config: config(imported),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("evm_contract_tx.imported", plancheck.ResourceActionUpdate),
+						expectKnownPlan{"evm_contract_tx.imported"},
+					},
+					PostApplyPostRefresh: []plancheck.PlanCheck{plancheck.ExpectEmptyPlan()},
+				},
+				Check: resource.TestCheckResourceAttrPair("evm_contract_tx.imported", "tx_id", "evm_contract_tx.transfer", "tx_id"),
+			},
+			{
+				Config:   config(imported),
+				PlanOnly: true,
+			},
+		},
+	})
+}
@@ -0,0 +1,284 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"terraform-provider-evm/internal/utils"
+
+	"github.com/ethereum/go-ethereum"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+func NewCreate2FactoryResource() resource.Resource {
+	return &create2FactoryResource{}
+}
+
+type create2FactoryResource struct {
+	providerData
+}
+
+func (*create2FactoryResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_create2_factory"
+}
+
+func (*create2FactoryResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Resource ensuring that the [deterministic deployment proxy](https://github.com/Arachnid/deterministic-deployment-proxy), the default `create2_factory` of `evm_contract`, is deployed, e.g. on a fresh development node. When the factory is missing, the signer funds the one-time deployer account and the presigned deployment transaction is broadcast. The transaction is not protected from replays with the chain ID (pre-EIP-155), which some nodes reject by default (e.g. Geth without `--rpc.allow-unprotected-txs`).",
+		Attributes: feeAttributes(signerAttributes(map[string]schema.Attribute{
+			"address": schema.StringAttribute{
+				MarkdownDescription: "Address of the factory, which is the same on every chain",
+				Computed:            true,
+				Default:             stringdefault.StaticString(defaultCreate2Factory.Hex()),
+			},
+			"deployer": schema.StringAttribute{
+				MarkdownDescription: "Address of the one-time account deploying the factory with the presigned transaction",
+				Computed:            true,
+				Default:             stringdefault.StaticString(create2FactoryDeployer.Hex()),
+			},
+			"funding_tx_id": schema.StringAttribute{
+				MarkdownDescription: "Hash of the transaction funding the deployer, null when the factory is already deployed or the deployer is already funded",
+				Computed:            true,
+				PlanModifiers:       []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+			},
+			"tx_id": schema.StringAttribute{
+				MarkdownDescription: "Hash of the factory deployment transaction, null when the factory is already deployed",
+				Computed:            true,
+				PlanModifiers:       []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+			},
+		})),
+	}
+}
+
+func (r *create2FactoryResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(providerData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected providerData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.providerData = data
+}
+
+func (*create2FactoryResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var model create2FactoryModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	model.signerConfig().validate(&resp.Diagnostics)
+	model.feeConfig().validate(&resp.Diagnostics)
+}
+
+func (r *create2FactoryResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var model create2FactoryModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	model.FundingTxId = types.StringNull()
+	model.TxId = types.StringNull()
+	r.deployFactory(ctx, &model, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, model)...)
+}
+
+// Read removes the factory from the state when it's not deployed anymore, e.g. after the development node reset.
+func (r *create2FactoryResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	code, err := r.client.CodeAt(ctx, defaultCreate2Factory, nil)
+	if err != nil {
+		resp.Diagnostics.AddError("Cannot retrieve deployed code", err.Error())
+		return
+	}
+	if len(code) == 0 {
+		tflog.Warn(ctx, fmt.Sprintf("There is no code at %s, removing CREATE2 factory from the state", defaultCreate2Factory.Hex()))
+		resp.State.RemoveResource(ctx)
+	}
+}
+
+// Update only stores the signer and fees, which are used when the factory is deployed.
+func (r *create2FactoryResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var model create2FactoryModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if model.From.IsUnknown() {
+		signer := model.signerConfig().resolve(r.providerData, &resp.Diagnostics)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		model.From = types.StringValue(signer.address.Hex())
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, model)...)
+}
+
+func (*create2FactoryResource) Delete(context.Context, resource.DeleteRequest, *resource.DeleteResponse) {
+}
+
+type create2FactoryModel struct {
+	Signer               types.String  `tfsdk:"signer"`
+	Keystore             types.String  `tfsdk:"keystore"`
+	KeystorePassword     types.String  `tfsdk:"keystore_password"`
+	SignerName           types.String  `tfsdk:"signer_name"`
+	From                 types.String  `tfsdk:"from"`
+	Impersonate          types.String  `tfsdk:"impersonate"`
+	GasLimit             types.Int64   `tfsdk:"gas_limit"`
+	GasLimitMultiplier   types.Float64 `tfsdk:"gas_limit_multiplier"`
+	MaxFeePerGas         types.String  `tfsdk:"max_fee_per_gas"`
+	MaxPriorityFeePerGas types.String  `tfsdk:"max_priority_fee_per_gas"`
+	GasPrice             types.String  `tfsdk:"gas_price"`
+	Address              types.String  `tfsdk:"address"`
+	Deployer             types.String  `tfsdk:"deployer"`
+	FundingTxId          types.String  `tfsdk:"funding_tx_id"`
+	TxId                 types.String  `tfsdk:"tx_id"`
+}
+
+func (m create2FactoryModel) signerConfig() signerConfig {
+	return signerConfig{m.Signer, m.Keystore, m.KeystorePassword, m.SignerName, m.From, m.Impersonate}
+}
+
+func (m create2FactoryModel) feeConfig() feeConfig {
+	return feeConfig{m.GasLimit, m.GasLimitMultiplier, m.MaxFeePerGas, m.MaxPriorityFeePerGas, m.GasPrice}
+}
+
+// deployFactory deploys the factory unless it's already deployed, funding the deployer with the cost of
+// the deployment transaction first.
+func (r *create2FactoryResource) deployFactory(ctx context.Context, model *create2FactoryModel, diags *diag.Diagnostics) {
+	signer := model.signerConfig().resolve(r.providerData, diags)
+	if diags.HasError() {
+		return
+	}
+	model.From = types.StringValue(signer.address.Hex())
+
+	code, err := r.client.CodeAt(ctx, defaultCreate2Factory, nil)
+	if err != nil {
+		diags.AddError("Cannot retrieve deployed code", err.Error())
+		return
+	}
+	if len(code) > 0 {
+		tflog.Info(ctx, fmt.Sprintf("CREATE2 factory is already deployed at %s", defaultCreate2Factory.Hex()))
+		return
+	}
+
+	deployment := new(ethTypes.Transaction)
+	if err := deployment.UnmarshalBinary(create2FactoryDeployment); err != nil {
+		diags.AddError("Cannot decode factory deployment", err.Error())
+		return
+	}
+	nonce, err := r.client.PendingNonceAt(ctx, create2FactoryDeployer)
+	if err != nil {
+		diags.AddError("Cannot retrieve deployer nonce", err.Error())
+		return
+	}
+	if nonce != deployment.Nonce() {
+		diags.AddError(
+			"Cannot deploy CREATE2 factory",
+			fmt.Sprintf("Deployer %s has nonce %d, while the presigned deployment transaction has nonce %d. The factory cannot be deployed at %s on this chain", create2FactoryDeployer.Hex(), nonce, deployment.Nonce(), defaultCreate2Factory.Hex()),
+		)
+		return
+	}
+
+	balance, err := r.client.BalanceAt(ctx, create2FactoryDeployer, nil)
+	if err != nil {
+		diags.AddError("Cannot retrieve deployer balance", err.Error())
+		return
+	}
+	if balance.Cmp(deployment.Cost()) < 0 {
+		r.fundDeployer(ctx, model, signer, new(big.Int).Sub(deployment.Cost(), balance), diags)
+		if diags.HasError() {
+			return
+		}
+	}
+
+	txHash, err := r.sendRawTransaction(ctx, create2FactoryDeployment)
+	if err != nil {
+		diags.AddError(
+			"Cannot deploy CREATE2 factory",
+			fmt.Sprintf("Presigned transaction %s is rejected, the node may not accept transactions without chain ID: %v", deployment.Hash().Hex(), err),
+		)
+		return
+	}
+	receipt, err := waitMined(ctx, r.client, txHash)
+	if err != nil {
+		diags.AddError("Error while waiting for transaction to be mined", err.Error())
+		return
+	}
+	if receipt.Status != ethTypes.ReceiptStatusSuccessful {
+		diags.AddError("Cannot deploy CREATE2 factory", fmt.Sprintf("Transaction %s failed in block %v", txHash.Hex(), receipt.BlockNumber))
+		return
+	}
+	model.TxId = types.StringValue(txHash.Hex())
+}
+
+// fundDeployer transfers the amount missing to pay for the deployment to the deployer.
+func (r *create2FactoryResource) fundDeployer(ctx context.Context, model *create2FactoryModel, signer *txSigner, amount *big.Int, diags *diag.Diagnostics) {
+	chainID, err := r.client.ChainID(ctx)
+	if err != nil {
+		diags.AddError("Cannot retrieve chain ID", err.Error())
+		return
+	}
+	auth, err := signer.transactOpts(ctx, chainID)
+	if err != nil {
+		diags.AddError("Error creating signer", err.Error())
+		return
+	}
+	fees := model.feeConfig().withDefaults(r.fees).resolve(diags)
+	if diags.HasError() {
+		return
+	}
+	auth.Value = amount
+
+	call := txCall{
+		msg:    ethereum.CallMsg{From: signer.address, To: &create2FactoryDeployer, Value: amount},
+		target: create2FactoryDeployer.Hex(),
+		method: "value transfer",
+	}
+	txHash, err := r.sendTransaction(ctx, signer, auth, chainID, fees, buildTransfer(ctx, r.client, create2FactoryDeployer))
+	if err != nil {
+		utils.ParseNodeError(signer.address.Hex(), amount, err, diags)
+		if !diags.HasError() {
+			diags.AddError("Transfer error", fmt.Sprintf("Signer %s\n%v", signer.address.Hex(), err))
+		}
+		return
+	}
+
+	receipt, err := waitMined(ctx, r.client, txHash)
+	if err != nil {
+		diags.AddError("Error while waiting for transaction to be mined", err.Error())
+		return
+	}
+	call.checkReceipt(ctx, r.client, receipt, diags)
+	if diags.HasError() {
+		return
+	}
+	model.FundingTxId = types.StringValue(txHash.Hex())
+}
@@ -0,0 +1,59 @@
+package provider
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccResourceCreate2Factory(t *testing.T) {
+	factory := `resource "evm_create2_factory" "factory" {
+		signer = "` + faucetPk + `"
+	}
+
+	resource "evm_contract" "token" {
+		artifact_path = "./testdata/Token.json"
+		signer = "` + faucetPk + `"
+		constructor_args = ["Name", "SYM", 1000, 18]
+		salt = "0x01"
+		depends_on = [evm_create2_factory.factory]
+	}
+	`
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: factory,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("evm_create2_factory.factory", "address", "0x4e59b44847b379578588920cA78FbF26c0B4956C"),
+					resource.TestCheckResourceAttr("evm_create2_factory.factory", "deployer", "0x3fAB184622Dc19b6109349B94811493BF2a45362"),
+					resource.TestCheckResourceAttrSet("evm_create2_factory.factory", "funding_tx_id"),
+					resource.TestCheckResourceAttrSet("evm_create2_factory.factory", "tx_id"),
+					resource.TestCheckResourceAttr("evm_create2_factory.factory", "from", faucetAddr.Hex()),
+					resource.TestCheckResourceAttrSet("evm_contract.token", "tx_id"),
+				),
+			},
+			{
+				// Fee changes are only stored
+				Config: strings.Replace(factory, `signer = "`+faucetPk+`"`, `signer = "`+faucetPk+`"
+					gas_limit = 200000`, 1),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("evm_create2_factory.factory", "gas_limit", "200000"),
+					resource.TestCheckResourceAttr("evm_create2_factory.factory", "from", faucetAddr.Hex()),
+				),
+			},
+			{
+				// Factory is deployed only once
+				Config: factory + `resource "evm_create2_factory" "existing" {
+					signer = "` + faucetPk + `"
+				}`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckNoResourceAttr("evm_create2_factory.existing", "funding_tx_id"),
+					resource.TestCheckNoResourceAttr("evm_create2_factory.existing", "tx_id"),
+				),
+			},
+		},
+	})
+}
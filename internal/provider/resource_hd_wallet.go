@@ -0,0 +1,251 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"terraform-provider-evm/internal/utils"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+const (
+	defaultMnemonicWords  = 12
+	defaultDerivationPath = "m/44'/60'/0'/0/" + utils.DerivationPathIndex
+)
+
+func NewHdWalletResource() resource.Resource {
+	return &hdWalletResource{}
+}
+
+type hdWalletResource struct{}
+
+func (*hdWalletResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_hd_wallet"
+}
+
+func (*hdWalletResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Hierarchical deterministic wallet deriving multiple accounts from a single [BIP-39](https://github.com/bitcoin/bips/blob/master/bip-0039.mediawiki) mnemonic.",
+		Attributes: map[string]schema.Attribute{
+			"mnemonic": schema.StringAttribute{
+				MarkdownDescription: "BIP-39 mnemonic phrase. Generated randomly when not specified",
+				Optional:            true,
+				Computed:            true,
+				Sensitive:           true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"passphrase": schema.StringAttribute{
+				MarkdownDescription: "Optional BIP-39 passphrase used together with the mnemonic to calculate the seed",
+				Optional:            true,
+				Sensitive:           true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"words": schema.Int64Attribute{
+				MarkdownDescription: fmt.Sprintf("Number of words in generated mnemonic, one of 12, 15, 18, 21 or 24. Defaults to %d", defaultMnemonicWords),
+				Optional:            true,
+				Computed:            true,
+				Default:             int64default.StaticInt64(defaultMnemonicWords),
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"path": schema.StringAttribute{
+				MarkdownDescription: fmt.Sprintf("[BIP-32](https://github.com/bitcoin/bips/blob/master/bip-0032.mediawiki) derivation path template with `%s` placeholder substituted by the account index. Defaults to `%s`", utils.DerivationPathIndex, defaultDerivationPath),
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString(defaultDerivationPath),
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"account_count": schema.Int64Attribute{
+				MarkdownDescription: "Number of accounts to derive. Defaults to 1",
+				Optional:            true,
+				Computed:            true,
+				Default:             int64default.StaticInt64(1),
+			},
+			"accounts": schema.ListNestedAttribute{
+				MarkdownDescription: "Derived accounts in the order of their index",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"pk": schema.StringAttribute{
+							MarkdownDescription: "Derived private key (32-byte hex value, no `0x` prefix)",
+							Computed:            true,
+							Sensitive:           true,
+						},
+						"pub_key": schema.StringAttribute{
+							MarkdownDescription: "Public key calculated from the private key (64-byte hex value, no `0x` prefix)",
+							Computed:            true,
+						},
+						"address": schema.StringAttribute{
+							MarkdownDescription: "EVM address calculated from the private key (20-byte hex value, with `0x` prefix). It has mixed case checksum according to [ERC-55](https://eips.ethereum.org/EIPS/eip-55)",
+							Computed:            true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (*hdWalletResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var model hdWalletModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !model.Words.IsNull() && !model.Words.IsUnknown() {
+		words := model.Words.ValueInt64()
+		if words < 12 || words > 24 || words%3 != 0 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("words"),
+				"Invalid mnemonic length",
+				fmt.Sprintf("Expected 12, 15, 18, 21 or 24 words, got %d", words),
+			)
+		}
+	}
+
+	if !model.AccountCount.IsNull() && !model.AccountCount.IsUnknown() && model.AccountCount.ValueInt64() < 1 {
+		resp.Diagnostics.AddAttributeError(path.Root("account_count"), "Invalid account count", "At least one account should be derived")
+	}
+
+	if !model.Path.IsNull() && !model.Path.IsUnknown() {
+		if _, err := utils.AccountDerivationPath(model.Path.ValueString(), 0); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("path"), "Invalid derivation path", err.Error())
+		}
+	}
+
+	if !model.Mnemonic.IsNull() && !model.Mnemonic.IsUnknown() {
+		if _, err := utils.MnemonicToSeed(model.Mnemonic.ValueString(), ""); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("mnemonic"), "Invalid mnemonic", err.Error())
+		}
+	}
+}
+
+// Create generates the mnemonic unless it is imported and derives accounts.
+func (*hdWalletResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan hdWalletModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if plan.Mnemonic.IsUnknown() || plan.Mnemonic.IsNull() {
+		mnemonic, err := utils.NewMnemonic(int(plan.Words.ValueInt64()))
+		if err != nil {
+			resp.Diagnostics.AddError("Unexpected error on mnemonic generation", err.Error())
+			return
+		}
+		plan.Mnemonic = types.StringValue(mnemonic)
+	}
+
+	plan.deriveAccounts(&resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+// Read does not need to perform any operations as the state in ReadResourceResponse is already populated.
+func (*hdWalletResource) Read(context.Context, resource.ReadRequest, *resource.ReadResponse) {
+
+}
+
+// Update derives accounts again when their count changes, other attributes require replacement.
+func (*hdWalletResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan hdWalletModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.deriveAccounts(&resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+// Delete does not need to explicitly call resp.State.RemoveResource() as this is automatically handled by the
+// [framework](https://github.com/hashicorp/terraform-plugin-framework/pull/301).
+func (*hdWalletResource) Delete(context.Context, resource.DeleteRequest, *resource.DeleteResponse) {
+}
+
+type hdWalletModel struct {
+	Mnemonic     types.String `tfsdk:"mnemonic"`
+	Passphrase   types.String `tfsdk:"passphrase"`
+	Words        types.Int64  `tfsdk:"words"`
+	Path         types.String `tfsdk:"path"`
+	AccountCount types.Int64  `tfsdk:"account_count"`
+	Accounts     types.List   `tfsdk:"accounts"`
+}
+
+var hdWalletAccountType = types.ObjectType{
+	AttrTypes: map[string]attr.Type{
+		"pk":      types.StringType,
+		"pub_key": types.StringType,
+		"address": types.StringType,
+	},
+}
+
+func (m *hdWalletModel) deriveAccounts(diags *diag.Diagnostics) {
+	seed, err := utils.MnemonicToSeed(m.Mnemonic.ValueString(), m.Passphrase.ValueString())
+	if err != nil {
+		diags.AddAttributeError(path.Root("mnemonic"), "Invalid mnemonic", err.Error())
+		return
+	}
+
+	accounts := make([]attr.Value, m.AccountCount.ValueInt64())
+	for i := range accounts {
+		derivationPath, err := utils.AccountDerivationPath(m.Path.ValueString(), i)
+		if err != nil {
+			diags.AddAttributeError(path.Root("path"), "Invalid derivation path", err.Error())
+			return
+		}
+
+		privateKey, err := utils.DeriveKey(seed, derivationPath)
+		if err != nil {
+			diags.AddError("Error deriving account", fmt.Sprintf("Account %d (%s): %v", i, derivationPath, err))
+			return
+		}
+
+		pk, pubKey, address := encodeKey(privateKey)
+		account, objectDiags := types.ObjectValue(hdWalletAccountType.AttrTypes, map[string]attr.Value{
+			"pk":      types.StringValue(pk),
+			"pub_key": types.StringValue(pubKey),
+			"address": types.StringValue(address),
+		})
+		diags.Append(objectDiags...)
+		if diags.HasError() {
+			return
+		}
+		accounts[i] = account
+	}
+
+	list, listDiags := types.ListValue(hdWalletAccountType, accounts)
+	diags.Append(listDiags...)
+	m.Accounts = list
+}
@@ -0,0 +1,54 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccHdWallet(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `resource "evm_hd_wallet" "invalid" {
+					mnemonic = "abandon abandon abandon"
+				}`,
+				ExpectError: regexp.MustCompile(`Invalid mnemonic`),
+			},
+			{
+				Config: `resource "evm_hd_wallet" "generated" {
+					words = 24
+					account_count = 2
+				}`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr("evm_hd_wallet.generated", "mnemonic", regexp.MustCompile(`^(\w+ ){23}\w+$`)),
+					resource.TestCheckResourceAttr("evm_hd_wallet.generated", "accounts.#", "2"),
+					resource.TestMatchResourceAttr("evm_hd_wallet.generated", "accounts.1.pk", regexp.MustCompile(`[A-Fa-f0-9]{64}`)),
+					resource.TestMatchResourceAttr("evm_hd_wallet.generated", "accounts.1.pub_key", regexp.MustCompile(`[A-Fa-f0-9]{128}`)),
+				),
+			},
+			{
+				Config: `resource "evm_hd_wallet" "imported" {
+					mnemonic = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+					account_count = 2
+				}`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("evm_hd_wallet.imported", "accounts.0.pk", "1ab42cc412b618bdea3a599e3c9bae199ebf030895b039e9db1e30dafb12b727"),
+					resource.TestCheckResourceAttr("evm_hd_wallet.imported", "accounts.0.address", "0x9858EfFD232B4033E47d90003D41EC34EcaEda94"),
+				),
+			},
+			{
+				Config: `resource "evm_hd_wallet" "imported" {
+					mnemonic = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+					account_count = 3
+				}`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("evm_hd_wallet.imported", "accounts.#", "3"),
+					resource.TestCheckResourceAttr("evm_hd_wallet.imported", "accounts.0.address", "0x9858EfFD232B4033E47d90003D41EC34EcaEda94"),
+				),
+			},
+		},
+	})
+}
@@ -60,23 +60,9 @@ func (*pkResource) Create(ctx context.Context, req resource.CreateRequest, resp
 		)
 		return
 	}
-	privateKeyBytes := crypto.FromECDSA(privateKey)
-
-	publicKey := privateKey.Public()
-	publicKeyECDSA, ok := publicKey.(*ecdsa.PublicKey)
-	if !ok {
-		resp.Diagnostics.AddError(
-			"Unexpected error casting public key to ECDSA",
-			err.Error(),
-		)
-		return
-	}
-
-	publicKeyBytes := crypto.FromECDSAPub(publicKeyECDSA)
-	address := crypto.PubkeyToAddress(*publicKeyECDSA).Hex()
-
-	plan.PK = types.StringValue(hexutil.Encode(privateKeyBytes)[2:])
-	plan.PubKey = types.StringValue(hexutil.Encode(publicKeyBytes)[2:])
+	pk, pubKey, address := encodeKey(privateKey)
+	plan.PK = types.StringValue(pk)
+	plan.PubKey = types.StringValue(pubKey)
 	plan.Address = types.StringValue(address)
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
@@ -110,3 +96,11 @@ type pkModel struct {
 	PubKey  types.String `tfsdk:"pub_key"`
 	Address types.String `tfsdk:"address"`
 }
+
+// encodeKey formats private key, public key and address the way key resources expose them.
+func encodeKey(privateKey *ecdsa.PrivateKey) (pk string, pubKey string, address string) {
+	pk = hexutil.Encode(crypto.FromECDSA(privateKey))[2:]
+	pubKey = hexutil.Encode(crypto.FromECDSAPub(&privateKey.PublicKey))[2:]
+	address = crypto.PubkeyToAddress(privateKey.PublicKey).Hex()
+	return pk, pubKey, address
+}
@@ -0,0 +1,557 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	proxyKindTransparent = "transparent"
+	proxyKindUUPS        = "uups"
+)
+
+var (
+	// erc1967ImplementationSlot is the storage slot of the proxy implementation, keccak256("eip1967.proxy.implementation") - 1.
+	erc1967ImplementationSlot = common.HexToHash("0x360894a13ba1a3210667c828492db98dca3e2076cc3735a920a3ca505d382bbc")
+	// erc1967AdminSlot is the storage slot of the proxy admin, keccak256("eip1967.proxy.admin") - 1.
+	erc1967AdminSlot = common.HexToHash("0xb53127684a568b3173ae13b9f8a6016e243e63b6e8ee1178d6a717850b5d6103")
+)
+
+func NewProxyResource() resource.Resource {
+	return &proxyResource{}
+}
+
+type proxyResource struct {
+	providerData
+}
+
+func (*proxyResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_proxy"
+}
+
+func (*proxyResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Resource used to deploy the implementation contract behind an [ERC-1967](https://eips.ethereum.org/EIPS/eip-1967) proxy. The implementation is deployed first, then the proxy is deployed with the encoded `initializer` call, which the proxy constructor delegates to the implementation, so the initialization can't be front-run. Changes of the deployment attributes replace both contracts.",
+		Attributes: feeAttributes(signerAttributes(map[string]schema.Attribute{
+			"artifact": schema.StringAttribute{
+				MarkdownDescription: "Content of the compiled artifact of the implementation containing ABI and binary in JSON format, which is stored in the state. Conflicts with `artifact_path`",
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"artifact_path": schema.StringAttribute{
+				MarkdownDescription: "Path to the compiled artifact of the implementation, relative to the working directory. Conflicts with `artifact`",
+				Optional:            true,
+			},
+			"artifact_format": schema.StringAttribute{
+				MarkdownDescription: "Format of the implementation artifact, see `artifact_format` of `evm_contract`. Detected from the artifact content by default",
+				Optional:            true,
+			},
+			"artifact_contract": schema.StringAttribute{
+				MarkdownDescription: "Implementation contract of the combined output containing several contracts, see `artifact_contract` of `evm_contract`",
+				Optional:            true,
+			},
+			"bytecode_hash": schema.StringAttribute{
+				MarkdownDescription: "Keccak256 hash of the implementation bytecode without compiler metadata. Changes of the bytecode replace the proxy",
+				Computed:            true,
+			},
+			"constructor_args": schema.ListAttribute{
+				MarkdownDescription: "String list of the implementation constructor arguments. See the list of supported types [here](../../README.md#deployment-and-transaction-args)",
+				ElementType:         types.StringType,
+				Optional:            true,
+				PlanModifiers:       []planmodifier.List{listplanmodifier.RequiresReplace()},
+			},
+			"proxy_artifact_path": schema.StringAttribute{
+				MarkdownDescription: "Path to the compiled artifact of the proxy contract. Constructor of the `transparent` proxy takes the implementation, the admin and the init data (e.g. `TransparentUpgradeableProxy` of OpenZeppelin Contracts), while constructor of the `uups` proxy takes the implementation and the init data (e.g. `ERC1967Proxy`)",
+				Required:            true,
+				PlanModifiers:       []planmodifier.String{stringplanmodifier.RequiresReplace()},
+			},
+			"kind": schema.StringAttribute{
+				MarkdownDescription: "Kind of the proxy, either `transparent` or `uups`",
+				Required:            true,
+				PlanModifiers:       []planmodifier.String{stringplanmodifier.RequiresReplace()},
+			},
+			"admin": schema.StringAttribute{
+				MarkdownDescription: "Admin of the `transparent` proxy passed to the proxy constructor, e.g. the owner of the `ProxyAdmin` deployed by `TransparentUpgradeableProxy` of OpenZeppelin Contracts 5. Upgrades of the `uups` proxy are authorized by the implementation, so the admin is not used",
+				Optional:            true,
+				PlanModifiers:       []planmodifier.String{stringplanmodifier.RequiresReplace()},
+			},
+			"initializer": schema.StringAttribute{
+				MarkdownDescription: "Signature of the implementation method initializing the proxy, e.g. `initialize(address,uint256)`. The proxy is deployed without the init data by default",
+				Optional:            true,
+				PlanModifiers:       []planmodifier.String{stringplanmodifier.RequiresReplace()},
+			},
+			"initializer_args": schema.ListAttribute{
+				MarkdownDescription: "String list of the `initializer` arguments. See the list of supported types [here](../../README.md#deployment-and-transaction-args)",
+				ElementType:         types.StringType,
+				Optional:            true,
+				PlanModifiers:       []planmodifier.List{listplanmodifier.RequiresReplace()},
+			},
+			"address": schema.StringAttribute{
+				MarkdownDescription: "Address of the proxy",
+				Computed:            true,
+				PlanModifiers:       []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+			},
+			"implementation_address": schema.StringAttribute{
+				MarkdownDescription: "Address of the implementation read from the ERC-1967 implementation slot of the proxy. Refresh reports upgrades of the proxy",
+				Computed:            true,
+				PlanModifiers:       []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+			},
+			"admin_address": schema.StringAttribute{
+				MarkdownDescription: "Address of the admin read from the ERC-1967 admin slot of the proxy, e.g. the `ProxyAdmin` contract deployed by the proxy. Null when the slot is empty, e.g. for `uups` proxy",
+				Computed:            true,
+				PlanModifiers:       []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+			},
+			"implementation_tx_id": schema.StringAttribute{
+				MarkdownDescription: "Hash of the implementation creation transaction",
+				Computed:            true,
+				PlanModifiers:       []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+			},
+			"tx_id": schema.StringAttribute{
+				MarkdownDescription: "Hash of the proxy creation transaction",
+				Computed:            true,
+				PlanModifiers:       []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+			},
+		})),
+	}
+}
+
+func (r *proxyResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(providerData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected providerData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.providerData = data
+}
+
+func (*proxyResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var model proxyModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	model.signerConfig().validate(&resp.Diagnostics)
+	model.feeConfig().validate(&resp.Diagnostics)
+
+	if model.Artifact.IsNull() && model.ArtifactPath.IsNull() {
+		resp.Diagnostics.AddAttributeError(path.Root("artifact_path"), "Missing artifact", "Either `artifact` or `artifact_path` is required")
+	}
+	if !model.Artifact.IsNull() && !model.ArtifactPath.IsNull() {
+		resp.Diagnostics.AddAttributeError(path.Root("artifact_path"), "Invalid artifact configuration", "`artifact` and `artifact_path` cannot be used together")
+	}
+
+	switch {
+	case model.Kind.IsUnknown() || model.Admin.IsUnknown():
+	case model.Kind.ValueString() == proxyKindTransparent && model.Admin.IsNull():
+		resp.Diagnostics.AddAttributeError(path.Root("admin"), "Missing admin", "`transparent` proxy requires `admin`")
+	case model.Kind.ValueString() == proxyKindUUPS && !model.Admin.IsNull():
+		resp.Diagnostics.AddAttributeError(path.Root("admin"), "Invalid admin configuration", "`uups` proxy doesn't use `admin`, as upgrades are authorized by the implementation")
+	}
+	parseProxyKind(model.Kind, &resp.Diagnostics)
+	parseAdmin(model.Admin, &resp.Diagnostics)
+	if model.Initializer.IsNull() && !model.InitializerArgs.IsNull() {
+		resp.Diagnostics.AddAttributeError(path.Root("initializer_args"), "Missing initializer", "`initializer_args` requires `initializer`")
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	artifact := loadArtifact(model.Artifact, model.ArtifactPath, model.ArtifactFormat, model.ArtifactContract, &resp.Diagnostics)
+	proxyArtifact := loadProxyArtifact(model.ProxyArtifactPath, &resp.Diagnostics)
+	if proxyArtifact != nil {
+		linkProxyBytecode(proxyArtifact, &resp.Diagnostics)
+	}
+	if proxyArtifact != nil && !model.Kind.IsUnknown() {
+		checkProxyConstructor(model.Kind.ValueString(), proxyArtifact, &resp.Diagnostics)
+	}
+	if artifact != nil && !model.Initializer.IsNull() && !model.Initializer.IsUnknown() && !model.InitializerArgs.IsUnknown() {
+		model.initData(ctx, artifact, &resp.Diagnostics)
+	}
+}
+
+// ModifyPlan calculates bytecode hash of the implementation, replacing the proxy when the bytecode changes.
+func (*proxyResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan proxyModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	artifact := loadArtifact(plan.Artifact, plan.ArtifactPath, plan.ArtifactFormat, plan.ArtifactContract, &resp.Diagnostics)
+	if artifact != nil {
+		plan.BytecodeHash = types.StringValue(artifact.bytecodeHash)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !req.State.Raw.IsNull() {
+		var bytecodeHash types.String
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("bytecode_hash"), &bytecodeHash)...)
+		if !plan.BytecodeHash.Equal(bytecodeHash) {
+			resp.RequiresReplace.Append(path.Root("bytecode_hash"))
+		}
+	}
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, plan)...)
+}
+
+func (r *proxyResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var model proxyModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.deployProxy(ctx, &model, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() && model.ImplementationTxId.IsUnknown() {
+		return
+	}
+
+	// Contracts deployed before the failure are recorded, so Terraform taints the proxy instead of losing them
+	for _, value := range []*types.String{&model.Address, &model.TxId, &model.ImplementationAddress, &model.AdminAddress} {
+		if value.IsUnknown() {
+			*value = types.StringNull()
+		}
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, model)...)
+}
+
+// Read removes the proxy from the state when there is no code anymore and refreshes the implementation
+// and the admin stored in the ERC-1967 slots.
+func (r *proxyResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var model proxyModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Proxy failed to deploy, the tainted state only records the implementation until the proxy is replaced
+	if model.Address.IsNull() {
+		return
+	}
+
+	address := common.HexToAddress(model.Address.ValueString())
+	code, err := r.client.CodeAt(ctx, address, nil)
+	if err != nil {
+		resp.Diagnostics.AddError("Cannot retrieve deployed code", err.Error())
+		return
+	}
+	if len(code) == 0 {
+		tflog.Warn(ctx, fmt.Sprintf("There is no code at %s, removing proxy from the state", address.Hex()))
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	r.readSlots(ctx, &model, &resp.Diagnostics)
+	resp.Diagnostics.Append(resp.State.Set(ctx, model)...)
+}
+
+// Update only stores the signer, the fees and the artifact with the same bytecode, as other attributes
+// replace the proxy.
+func (r *proxyResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var model, state proxyModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &model)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Admin is read from the slot, which is null for the uups proxy and not kept by the plan modifier
+	model.AdminAddress = state.AdminAddress
+	if model.From.IsUnknown() {
+		signer := model.signerConfig().resolve(r.providerData, &resp.Diagnostics)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		model.From = types.StringValue(signer.address.Hex())
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, model)...)
+}
+
+func (*proxyResource) Delete(context.Context, resource.DeleteRequest, *resource.DeleteResponse) {
+}
+
+type proxyModel struct {
+	Artifact              types.String  `tfsdk:"artifact"`
+	ArtifactPath          types.String  `tfsdk:"artifact_path"`
+	ArtifactFormat        types.String  `tfsdk:"artifact_format"`
+	ArtifactContract      types.String  `tfsdk:"artifact_contract"`
+	BytecodeHash          types.String  `tfsdk:"bytecode_hash"`
+	ConstructorArgs       types.List    `tfsdk:"constructor_args"`
+	ProxyArtifactPath     types.String  `tfsdk:"proxy_artifact_path"`
+	Kind                  types.String  `tfsdk:"kind"`
+	Admin                 types.String  `tfsdk:"admin"`
+	Initializer           types.String  `tfsdk:"initializer"`
+	InitializerArgs       types.List    `tfsdk:"initializer_args"`
+	Signer                types.String  `tfsdk:"signer"`
+	Keystore              types.String  `tfsdk:"keystore"`
+	KeystorePassword      types.String  `tfsdk:"keystore_password"`
+	SignerName            types.String  `tfsdk:"signer_name"`
+	From                  types.String  `tfsdk:"from"`
+	Impersonate           types.String  `tfsdk:"impersonate"`
+	GasLimit              types.Int64   `tfsdk:"gas_limit"`
+	GasLimitMultiplier    types.Float64 `tfsdk:"gas_limit_multiplier"`
+	MaxFeePerGas          types.String  `tfsdk:"max_fee_per_gas"`
+	MaxPriorityFeePerGas  types.String  `tfsdk:"max_priority_fee_per_gas"`
+	GasPrice              types.String  `tfsdk:"gas_price"`
+	Address               types.String  `tfsdk:"address"`
+	ImplementationAddress types.String  `tfsdk:"implementation_address"`
+	AdminAddress          types.String  `tfsdk:"admin_address"`
+	ImplementationTxId    types.String  `tfsdk:"implementation_tx_id"`
+	TxId                  types.String  `tfsdk:"tx_id"`
+}
+
+func (m proxyModel) signerConfig() signerConfig {
+	return signerConfig{m.Signer, m.Keystore, m.KeystorePassword, m.SignerName, m.From, m.Impersonate}
+}
+
+func (m proxyModel) feeConfig() feeConfig {
+	return feeConfig{m.GasLimit, m.GasLimitMultiplier, m.MaxFeePerGas, m.MaxPriorityFeePerGas, m.GasPrice}
+}
+
+// initData encodes the initializer call, checking that the initializer is declared by the implementation.
+// Init data is empty without the initializer.
+func (m proxyModel) initData(ctx context.Context, artifact *contractArtifact, diags *diag.Diagnostics) []byte {
+	if m.Initializer.IsNull() {
+		return []byte{}
+	}
+	call := newContractCall(ctx, m.Initializer, m.InitializerArgs, diags)
+	if call == nil {
+		return nil
+	}
+	signature := call.abi.Methods[call.method].Sig
+	for _, method := range artifact.abi.Methods {
+		if method.Sig == signature {
+			return call.input
+		}
+	}
+	diags.AddAttributeError(path.Root("initializer"), "Unknown initializer", fmt.Sprintf("Method %s is not declared in the implementation ABI", signature))
+	return nil
+}
+
+func parseProxyKind(kind types.String, diags *diag.Diagnostics) {
+	if kind.IsUnknown() || kind.ValueString() == proxyKindTransparent || kind.ValueString() == proxyKindUUPS {
+		return
+	}
+	diags.AddAttributeError(
+		path.Root("kind"),
+		"Invalid proxy kind",
+		fmt.Sprintf("Expected `%s` or `%s`, got `%s`", proxyKindTransparent, proxyKindUUPS, kind.ValueString()),
+	)
+}
+
+func parseAdmin(admin types.String, diags *diag.Diagnostics) common.Address {
+	if admin.IsNull() || admin.IsUnknown() {
+		return common.Address{}
+	}
+	if !common.IsHexAddress(admin.ValueString()) {
+		diags.AddAttributeError(path.Root("admin"), "Invalid admin address", fmt.Sprintf("Expected address, got '%s'", admin.ValueString()))
+		return common.Address{}
+	}
+	return common.HexToAddress(admin.ValueString())
+}
+
+// loadProxyArtifact loads the artifact of the proxy contract, returning nil when the path is unknown.
+func loadProxyArtifact(artifactPath types.String, diags *diag.Diagnostics) *contractArtifact {
+	if artifactPath.IsNull() || artifactPath.IsUnknown() {
+		return nil
+	}
+	data, err := os.ReadFile(artifactPath.ValueString())
+	if err != nil {
+		diags.AddAttributeError(path.Root("proxy_artifact_path"), "Cannot read artifact", err.Error())
+		return nil
+	}
+	artifact, err := newContractArtifact(string(data), "", "")
+	if err != nil {
+		diags.AddAttributeError(path.Root("proxy_artifact_path"), "Invalid artifact", err.Error())
+		return nil
+	}
+	return artifact
+}
+
+// checkProxyConstructor checks that the proxy constructor takes the arguments of the proxy kind.
+func checkProxyConstructor(kind string, artifact *contractArtifact, diags *diag.Diagnostics) {
+	expected := "(address,bytes)"
+	if kind == proxyKindTransparent {
+		expected = "(address,address,bytes)"
+	}
+	inputs := "("
+	for i, input := range artifact.abi.Constructor.Inputs {
+		if i > 0 {
+			inputs += ","
+		}
+		inputs += input.Type.String()
+	}
+	inputs += ")"
+	if inputs != expected {
+		diags.AddAttributeError(
+			path.Root("proxy_artifact_path"),
+			"Invalid proxy artifact",
+			fmt.Sprintf("Constructor of the `%s` proxy should take %s, got %s", kind, expected, inputs),
+		)
+	}
+}
+
+// linkProxyBytecode returns the creation bytecode of the proxy, which can't reference libraries, as their
+// addresses are not configured for the proxy.
+func linkProxyBytecode(proxyArtifact *contractArtifact, diags *diag.Diagnostics) []byte {
+	bytecode, err := proxyArtifact.LinkBytecode(nil)
+	if err != nil {
+		diags.AddAttributeError(path.Root("proxy_artifact_path"), "Cannot link libraries", err.Error())
+		return nil
+	}
+	return bytecode
+}
+
+// deployProxy deploys the implementation and then the proxy initialized with the init data.
+func (r *proxyResource) deployProxy(ctx context.Context, model *proxyModel, diags *diag.Diagnostics) {
+	artifact := loadArtifact(model.Artifact, model.ArtifactPath, model.ArtifactFormat, model.ArtifactContract, diags)
+	proxyArtifact := loadProxyArtifact(model.ProxyArtifactPath, diags)
+	if diags.HasError() {
+		return
+	}
+	if artifact == nil || proxyArtifact == nil {
+		diags.AddError("Missing artifact", "Artifacts of the implementation and the proxy are required")
+		return
+	}
+	if artifact.bytecodeHash != model.BytecodeHash.ValueString() {
+		diags.AddAttributeError(
+			path.Root("artifact_path"),
+			"Artifact changed",
+			fmt.Sprintf("Artifact has bytecode hash %s, while the plan was created with hash %s. Plan the changes again", artifact.bytecodeHash, model.BytecodeHash.ValueString()),
+		)
+		return
+	}
+	checkProxyConstructor(model.Kind.ValueString(), proxyArtifact, diags)
+	proxyBytecode := linkProxyBytecode(proxyArtifact, diags)
+	initCode := buildInitCode(ctx, artifact, types.MapNull(types.StringType), model.ConstructorArgs, diags)
+	initData := model.initData(ctx, artifact, diags)
+	admin := parseAdmin(model.Admin, diags)
+	if diags.HasError() {
+		return
+	}
+
+	chainID, err := r.client.ChainID(ctx)
+	if err != nil {
+		diags.AddError("Cannot retrieve chain ID", err.Error())
+		return
+	}
+
+	signer := model.signerConfig().resolve(r.providerData, diags)
+	if diags.HasError() {
+		return
+	}
+	model.From = types.StringValue(signer.address.Hex())
+
+	auth, err := signer.transactOpts(ctx, chainID)
+	if err != nil {
+		diags.AddError("Error creating signer", err.Error())
+		return
+	}
+
+	fees := model.feeConfig().withDefaults(r.fees).resolve(diags)
+	if diags.HasError() {
+		return
+	}
+
+	implementation := r.deploy(ctx, signer, auth, chainID, fees, txCall{
+		msg:    ethereum.CallMsg{From: signer.address, Data: initCode},
+		target: "new implementation",
+		method: "constructor",
+		abis:   []*abi.ABI{&artifact.abi},
+	}, buildDeploy(r.client, initCode), common.Address{}, nil, diags)
+	if diags.HasError() {
+		return
+	}
+	model.ImplementationAddress = types.StringValue(implementation.address.Hex())
+	model.ImplementationTxId = types.StringValue(implementation.txHash.Hex())
+
+	args := []interface{}{implementation.address}
+	if model.Kind.ValueString() == proxyKindTransparent {
+		args = append(args, admin)
+	}
+	proxyInput, err := proxyArtifact.abi.Pack("", append(args, initData)...)
+	if err != nil {
+		diags.AddAttributeError(path.Root("proxy_artifact_path"), "Error encoding proxy constructor args", err.Error())
+		return
+	}
+
+	proxyCode := append(proxyBytecode, proxyInput...)
+	proxy := r.deploy(ctx, signer, auth, chainID, fees, txCall{
+		msg:    ethereum.CallMsg{From: signer.address, Data: proxyCode},
+		target: fmt.Sprintf("new proxy of implementation %s", implementation.address.Hex()),
+		method: "constructor",
+		// Initializer reverts with errors of the implementation
+		abis: []*abi.ABI{&proxyArtifact.abi, &artifact.abi},
+	}, buildDeploy(r.client, proxyCode), common.Address{}, nil, diags)
+	if diags.HasError() {
+		return
+	}
+	model.Address = types.StringValue(proxy.address.Hex())
+	model.TxId = types.StringValue(proxy.txHash.Hex())
+
+	r.readSlots(ctx, model, diags)
+	if model.ImplementationAddress.ValueString() != implementation.address.Hex() {
+		diags.AddAttributeError(
+			path.Root("proxy_artifact_path"),
+			"Invalid proxy artifact",
+			fmt.Sprintf("Proxy %s stores implementation %s in the ERC-1967 implementation slot, while implementation %s was deployed", proxy.address.Hex(), model.ImplementationAddress.ValueString(), implementation.address.Hex()),
+		)
+	}
+}
+
+// readSlots reads the implementation and the admin from the ERC-1967 slots of the proxy.
+func (r *proxyResource) readSlots(ctx context.Context, model *proxyModel, diags *diag.Diagnostics) {
+	address := common.HexToAddress(model.Address.ValueString())
+	implementation, err := r.client.StorageAt(ctx, address, erc1967ImplementationSlot, nil)
+	if err != nil {
+		diags.AddError("Cannot read proxy implementation", err.Error())
+		return
+	}
+	admin, err := r.client.StorageAt(ctx, address, erc1967AdminSlot, nil)
+	if err != nil {
+		diags.AddError("Cannot read proxy admin", err.Error())
+		return
+	}
+
+	model.ImplementationAddress = types.StringValue(common.BytesToAddress(implementation).Hex())
+	model.AdminAddress = types.StringNull()
+	if adminAddress := common.BytesToAddress(admin); adminAddress != (common.Address{}) {
+		model.AdminAddress = types.StringValue(adminAddress.Hex())
+	}
+}
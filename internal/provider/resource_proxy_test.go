@@ -0,0 +1,153 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+// testCheckProxyValue checks the value stored by the initializer of the Initializable implementation behind the proxy.
+func testCheckProxyValue(name string, expected int64) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		proxy := common.HexToAddress(s.RootModule().Resources[name].Primary.Attributes["address"])
+		// value()
+		output, err := testClient.CallContract(context.Background(), ethereum.CallMsg{To: &proxy, Data: hexutil.MustDecode("0x3fa4f245")}, nil)
+		if err != nil {
+			return err
+		}
+		if value := new(big.Int).SetBytes(output); value.Cmp(big.NewInt(expected)) != 0 {
+			return fmt.Errorf("expected %s value %d, got %v", name, expected, value)
+		}
+		return nil
+	}
+}
+
+func TestAccResourceProxy(t *testing.T) {
+	admin := "0x000000000000000000000000000000000000dEaD"
+	proxyArtifact, err := os.ReadFile("./testdata/ERC1967Proxy.json")
+	if err != nil {
+		t.Fatalf("Cannot read artifact: %v", err)
+	}
+	var linked map[string]interface{}
+	if err := json.Unmarshal(proxyArtifact, &linked); err != nil {
+		t.Fatalf("Cannot parse artifact: %v", err)
+	}
+	linked["linkReferences"] = map[string]interface{}{"contracts/Lib.sol": map[string]interface{}{"Lib": []interface{}{map[string]interface{}{"start": 1, "length": 20}}}}
+	linkedProxyArtifact, _ := json.Marshal(linked)
+	linkedPath := filepath.Join(t.TempDir(), "LinkedProxy.json")
+	if err := os.WriteFile(linkedPath, linkedProxyArtifact, 0600); err != nil {
+		t.Fatalf("Cannot write artifact: %v", err)
+	}
+
+	config := func(name string, kind string, attributes string) string {
+		return fmt.Sprintf(`resource "evm_proxy" "%s" {
+			artifact_path = "./testdata/Initializable.json"
+			proxy_artifact_path = "./testdata/%s"
+			kind = "%s"
+			signer = "%s"
+			%s
+		}
+		`, name, map[string]string{proxyKindTransparent: "TransparentProxy.json", proxyKindUUPS: "ERC1967Proxy.json", "beacon": "ERC1967Proxy.json"}[kind], kind, faucetPk, attributes)
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      config("proxy", "beacon", ""),
+				ExpectError: regexp.MustCompile("Invalid proxy kind"),
+			},
+			{
+				Config:      config("proxy", proxyKindTransparent, ""),
+				ExpectError: regexp.MustCompile("Missing admin"),
+			},
+			{
+				Config:      config("proxy", proxyKindUUPS, `admin = "`+admin+`"`),
+				ExpectError: regexp.MustCompile("Invalid admin configuration"),
+			},
+			{
+				Config: `resource "evm_proxy" "proxy" {
+					artifact_path = "./testdata/Initializable.json"
+					proxy_artifact_path = "./testdata/TransparentProxy.json"
+					kind = "uups"
+					signer = "` + faucetPk + `"
+				}`,
+				ExpectError: regexp.MustCompile("Invalid proxy artifact"),
+			},
+			{
+				Config:      strings.Replace(config("proxy", proxyKindUUPS, ""), "./testdata/ERC1967Proxy.json", linkedPath, 1),
+				ExpectError: regexp.MustCompile("Cannot link libraries"),
+			},
+			{
+				Config:      config("proxy", proxyKindUUPS, `initializer = "init(uint256)"`+"\n"+`initializer_args = ["42"]`),
+				ExpectError: regexp.MustCompile("Unknown initializer"),
+			},
+			{
+				// Initializer reverts with zero value
+				Config:      config("proxy", proxyKindUUPS, `initializer = "initialize(uint256)"`+"\n"+`initializer_args = ["0"]`),
+				ExpectError: regexp.MustCompile("Deploy reverted"),
+			},
+			{
+				// Implementation deployed before the initializer reverted is recorded in the tainted state
+				Config: config("proxy", proxyKindUUPS, `initializer = "initialize(uint256)"`+"\n"+`initializer_args = ["3"]`),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("evm_proxy.proxy", plancheck.ResourceActionDestroyBeforeCreate),
+						expectPriorValue{"evm_proxy.proxy", "implementation_address", regexp.MustCompile(`^0x[A-Fa-f0-9]{40}$`)},
+						expectPriorValue{"evm_proxy.proxy", "implementation_tx_id", regexp.MustCompile(`^0x[a-f0-9]{64}$`)},
+					},
+				},
+				Check: testCheckProxyValue("evm_proxy.proxy", 3),
+			},
+			{
+				Config: config("transparent", proxyKindTransparent, `admin = "`+admin+`"`+"\n"+`initializer = "initialize(uint256)"`+"\n"+`initializer_args = ["42"]`) +
+					config("uups", proxyKindUUPS, `initializer = "initialize(uint256)"`+"\n"+`initializer_args = ["7"]`) +
+					config("uninitialized", proxyKindUUPS, ""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr("evm_proxy.transparent", "address", regexp.MustCompile(`^0x[A-Fa-f0-9]{40}$`)),
+					resource.TestMatchResourceAttr("evm_proxy.transparent", "implementation_address", regexp.MustCompile(`^0x[A-Fa-f0-9]{40}$`)),
+					resource.TestCheckResourceAttr("evm_proxy.transparent", "admin_address", admin),
+					resource.TestCheckResourceAttrSet("evm_proxy.transparent", "implementation_tx_id"),
+					resource.TestCheckResourceAttrSet("evm_proxy.transparent", "tx_id"),
+					resource.TestCheckResourceAttr("evm_proxy.transparent", "from", faucetAddr.Hex()),
+					testCheckProxyValue("evm_proxy.transparent", 42),
+					resource.TestCheckNoResourceAttr("evm_proxy.uups", "admin_address"),
+					testCheckProxyValue("evm_proxy.uups", 7),
+					testCheckProxyValue("evm_proxy.uninitialized", 0),
+				),
+			},
+			{
+				// Artifact with the same bytecode is stored without deploying the contracts again
+				Config: strings.Replace(config("uninitialized", proxyKindUUPS, ""), `artifact_path = "./testdata/Initializable.json"`, `artifact = file("./testdata/Initializable.json")`, 1),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{plancheck.ExpectResourceAction("evm_proxy.uninitialized", plancheck.ResourceActionUpdate)},
+				},
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckNoResourceAttr("evm_proxy.uninitialized", "artifact_path"),
+					testCheckProxyValue("evm_proxy.uninitialized", 0),
+				),
+			},
+			{
+				// Implementation with another bytecode replaces the proxy
+				Config: strings.Replace(config("uninitialized", proxyKindUUPS, `constructor_args = ["5"]`), "Initializable.json", "Immutable.json", 1),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{plancheck.ExpectResourceAction("evm_proxy.uninitialized", plancheck.ResourceActionDestroyBeforeCreate)},
+				},
+				Check: testCheckProxyValue("evm_proxy.uninitialized", 5),
+			},
+		},
+	})
+}
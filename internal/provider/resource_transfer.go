@@ -0,0 +1,307 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"terraform-provider-evm/internal/utils"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func NewTransferResource() resource.Resource {
+	return &transferResource{}
+}
+
+type transferResource struct {
+	providerData
+}
+
+func (*transferResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_transfer"
+}
+
+func (*transferResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	attributes := receiptAttributes(feeAttributes(signerAttributes(map[string]schema.Attribute{
+		"to": schema.StringAttribute{
+			MarkdownDescription: "Recipient address (20-byte hex with `0x` prefix), changes of the address replace the transfer",
+			Required:            true,
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.RequiresReplaceIf(
+					recipientChanged,
+					"Transfer is replaced when the recipient address changes",
+					"Transfer is replaced when the recipient address changes",
+				),
+			},
+		},
+		"value": schema.StringAttribute{
+			MarkdownDescription: "Amount to transfer, in wei or with a unit suffix (e.g. `0.1 ether` or `20 gwei`), changes replace the transfer",
+			Required:            true,
+			PlanModifiers:       []planmodifier.String{stringplanmodifier.RequiresReplace()},
+		},
+		"tx_id": schema.StringAttribute{
+			MarkdownDescription: "Transaction id of the transfer, populated after transaction is mined",
+			Computed:            true,
+		},
+	})))
+
+	// Computed sender address is kept unless `from` is configured, the sender of the changed signer is resolved by ModifyPlan
+	from := attributes["from"].(schema.StringAttribute)
+	from.PlanModifiers = append(from.PlanModifiers, stringplanmodifier.UseStateForUnknown())
+	attributes["from"] = from
+
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Resource for transferring native currency (e.g. ETH) to an account, for example to fund a deployer generated with `evm_random_pk`. Changes of the fee attributes, or of the signer attributes resolving to the same sender address, are stored without sending the transfer again.",
+		Attributes:          attributes,
+	}
+}
+
+func (r *transferResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(providerData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected providerData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.providerData = data
+}
+
+func (*transferResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var model transferModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	model.signerConfig().validate(&resp.Diagnostics)
+	model.feeConfig().validate(&resp.Diagnostics)
+	parseValue(model.Value, &resp.Diagnostics)
+
+	if !model.To.IsNull() && !model.To.IsUnknown() && !common.IsHexAddress(model.To.ValueString()) {
+		resp.Diagnostics.AddAttributeError(path.Root("to"), "Invalid recipient", fmt.Sprintf("'%s' is not a valid address", model.To.ValueString()))
+	}
+}
+
+// ModifyPlan resolves the sender of the changed signer attributes, replacing the transfer when the sender address
+// changes or is not known until apply.
+func (r *transferResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() || req.State.Raw.IsNull() {
+		return
+	}
+
+	var plan, state transferModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if plan.Signer.Equal(state.Signer) && plan.Keystore.Equal(state.Keystore) && plan.KeystorePassword.Equal(state.KeystorePassword) &&
+		plan.SignerName.Equal(state.SignerName) && plan.From.Equal(state.From) {
+		return
+	}
+
+	// Planned `from` is kept from the state unless it is configured
+	config := plan.signerConfig()
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("from"), &config.From)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if r.client == nil || config.Signer.IsUnknown() || config.Keystore.IsUnknown() || config.KeystorePassword.IsUnknown() ||
+		config.SignerName.IsUnknown() || config.From.IsUnknown() {
+		plan.From = types.StringUnknown()
+	} else if signer := config.resolve(r.providerData, &resp.Diagnostics); signer != nil {
+		if config.From.IsNull() {
+			plan.From = types.StringValue(signer.address.Hex())
+		}
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if plan.From.IsUnknown() || common.HexToAddress(plan.From.ValueString()) != common.HexToAddress(state.From.ValueString()) {
+		resp.RequiresReplace.Append(path.Root("from"))
+	}
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, plan)...)
+}
+
+func recipientChanged(_ context.Context, req planmodifier.StringRequest, resp *stringplanmodifier.RequiresReplaceIfFuncResponse) {
+	resp.RequiresReplace = req.PlanValue.IsUnknown() || common.HexToAddress(req.PlanValue.ValueString()) != common.HexToAddress(req.StateValue.ValueString())
+}
+
+func (r *transferResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	r.sendTransfer(ctx, &req.Plan, &resp.State, &resp.Diagnostics)
+}
+
+func (*transferResource) Read(context.Context, resource.ReadRequest, *resource.ReadResponse) {
+
+}
+
+// Update stores the signer, the fee attributes and the recipient given in another case, as changes of the sender,
+// the recipient address and the value replace the transfer.
+func (*transferResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, model transferModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	model.Signer = plan.Signer
+	model.Keystore = plan.Keystore
+	model.KeystorePassword = plan.KeystorePassword
+	model.SignerName = plan.SignerName
+	model.From = plan.From
+	model.Impersonate = plan.Impersonate
+	model.To = plan.To
+	model.GasLimit = plan.GasLimit
+	model.GasLimitMultiplier = plan.GasLimitMultiplier
+	model.MaxFeePerGas = plan.MaxFeePerGas
+	model.MaxPriorityFeePerGas = plan.MaxPriorityFeePerGas
+	model.GasPrice = plan.GasPrice
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, model)...)
+}
+
+func (*transferResource) Delete(context.Context, resource.DeleteRequest, *resource.DeleteResponse) {
+}
+
+type transferModel struct {
+	Signer               types.String  `tfsdk:"signer"`
+	Keystore             types.String  `tfsdk:"keystore"`
+	KeystorePassword     types.String  `tfsdk:"keystore_password"`
+	SignerName           types.String  `tfsdk:"signer_name"`
+	From                 types.String  `tfsdk:"from"`
+	Impersonate          types.String  `tfsdk:"impersonate"`
+	GasLimit             types.Int64   `tfsdk:"gas_limit"`
+	GasLimitMultiplier   types.Float64 `tfsdk:"gas_limit_multiplier"`
+	MaxFeePerGas         types.String  `tfsdk:"max_fee_per_gas"`
+	MaxPriorityFeePerGas types.String  `tfsdk:"max_priority_fee_per_gas"`
+	GasPrice             types.String  `tfsdk:"gas_price"`
+	To                   types.String  `tfsdk:"to"`
+	Value                types.String  `tfsdk:"value"`
+	TxId                 types.String  `tfsdk:"tx_id"`
+	BlockNumber          types.Int64   `tfsdk:"block_number"`
+	BlockHash            types.String  `tfsdk:"block_hash"`
+	GasUsed              types.Int64   `tfsdk:"gas_used"`
+	EffectiveGasPrice    types.String  `tfsdk:"effective_gas_price"`
+	FeePaidWei           types.String  `tfsdk:"fee_paid_wei"`
+	Nonce                types.Int64   `tfsdk:"nonce"`
+}
+
+func (m transferModel) signerConfig() signerConfig {
+	return signerConfig{m.Signer, m.Keystore, m.KeystorePassword, m.SignerName, m.From, m.Impersonate}
+}
+
+func (m *transferModel) setReceipt(receipt txReceipt) {
+	m.BlockNumber = receipt.BlockNumber
+	m.BlockHash = receipt.BlockHash
+	m.GasUsed = receipt.GasUsed
+	m.EffectiveGasPrice = receipt.EffectiveGasPrice
+	m.FeePaidWei = receipt.FeePaidWei
+	m.Nonce = receipt.Nonce
+	m.From = receipt.From
+}
+
+func (m transferModel) feeConfig() feeConfig {
+	return feeConfig{m.GasLimit, m.GasLimitMultiplier, m.MaxFeePerGas, m.MaxPriorityFeePerGas, m.GasPrice}
+}
+
+func (r *transferResource) sendTransfer(ctx context.Context, plan *tfsdk.Plan,
+	state *tfsdk.State, respDiags *diag.Diagnostics) {
+
+	var model transferModel
+
+	diags := plan.Get(ctx, &model)
+	respDiags.Append(diags...)
+	if respDiags.HasError() {
+		return
+	}
+
+	chainID, err := r.client.ChainID(ctx)
+	if err != nil {
+		respDiags.AddError("Cannot retrieve chain ID", err.Error())
+		return
+	}
+
+	signer := model.signerConfig().resolve(r.providerData, respDiags)
+	if respDiags.HasError() {
+		return
+	}
+	signerAddress := signer.address.Hex()
+
+	auth, err := signer.transactOpts(ctx, chainID)
+	if err != nil {
+		respDiags.AddError("Error creating signer", err.Error())
+		return
+	}
+
+	fees := model.feeConfig().withDefaults(r.fees).resolve(respDiags)
+	auth.Value = parseValue(model.Value, respDiags)
+	if respDiags.HasError() {
+		return
+	}
+
+	to := common.HexToAddress(model.To.ValueString())
+	call := txCall{
+		msg:    ethereum.CallMsg{From: signer.address, To: &to, Value: auth.Value},
+		target: to.Hex(),
+		method: "value transfer",
+	}
+
+	txHash, err := r.sendTransaction(ctx, signer, auth, chainID, fees, buildTransfer(ctx, r.client, to))
+
+	if err != nil {
+		utils.ParseNodeError(signerAddress, auth.Value, err, respDiags)
+		if respDiags.HasError() {
+			return
+		}
+
+		if reason, ok := call.revertReason(ctx, r.client, err); ok {
+			respDiags.AddError("Transfer reverted", call.details(reason))
+			return
+		}
+
+		respDiags.AddError(
+			"Transfer error",
+			fmt.Sprintf("Signer %s\n%v", signerAddress, err),
+		)
+		return
+	}
+
+	// Wait until transaction is mined
+	receipt, err := waitMined(ctx, r.client, txHash)
+	if err != nil {
+		respDiags.AddError("Error while waiting for transaction to be mined", err.Error())
+		return
+	}
+
+	call.checkReceipt(ctx, r.client, receipt, respDiags)
+	if respDiags.HasError() {
+		return
+	}
+
+	model.TxId = types.StringValue(txHash.String())
+	model.setReceipt(newTxReceipt(receipt, auth.Nonce.Uint64(), signer.address))
+
+	respDiags.Append(state.Set(ctx, model)...)
+}
@@ -0,0 +1,113 @@
+package provider
+
+import (
+	"math/big"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+)
+
+func TestAccResourceTransfer(t *testing.T) {
+	config := func(fundAttributes string) string {
+		return `resource "evm_random_pk" "deployer" {}
+
+		resource "evm_transfer" "fund" {
+			signer = "` + faucetPk + `"
+			to = evm_random_pk.deployer.address
+			value = "2 ether"
+			` + fundAttributes + `
+		}
+
+		resource "evm_transfer" "refund" {
+			signer = evm_random_pk.deployer.pk
+			to = "` + faucetAddr.Hex() + `"
+			value = "0.5 ether"
+			gas_price = "10 gwei"
+			depends_on = [evm_transfer.fund]
+		}
+		`
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `resource "evm_transfer" "invalid" {
+					signer = "` + faucetPk + `"
+					to = "0xdead"
+					value = "1 ether"
+				}
+				`,
+				ExpectError: regexp.MustCompile("Invalid recipient"),
+			},
+			{
+				Config: config(""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr("evm_transfer.fund", "tx_id", regexp.MustCompile(`0x[A-Fa-f0-9]{64}`)),
+					resource.TestCheckResourceAttrSet("evm_transfer.fund", "block_number"),
+					// Plain transfer costs 21000 gas
+					resource.TestCheckResourceAttr("evm_transfer.refund", "fee_paid_wei", "210000000000000"),
+					resource.TestCheckResourceAttr("evm_transfer.refund", "gas_used", "21000"),
+					resource.TestCheckResourceAttr("evm_transfer.refund", "effective_gas_price", "10000000000"),
+					resource.TestCheckResourceAttr("evm_transfer.refund", "nonce", "0"),
+					resource.TestCheckResourceAttrPair("evm_transfer.refund", "from", "evm_random_pk.deployer", "address"),
+					resource.TestMatchResourceAttr("evm_transfer.refund", "block_hash", regexp.MustCompile(`^0x[a-f0-9]{64}$`)),
+					testCheckBalance("evm_random_pk.deployer", "address", big.NewInt(15*params.Ether/10-210000*params.GWei)),
+				),
+			},
+			{
+				// Fee changes are stored without sending the transfer again
+				Config: config(`gas_price = "20 gwei"`),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("evm_transfer.fund", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("evm_transfer.fund", "gas_price", "20 gwei"),
+					testCheckBalance("evm_random_pk.deployer", "address", big.NewInt(15*params.Ether/10-210000*params.GWei)),
+				),
+			},
+			{
+				// Signer resolving to the same sender and the recipient in another case are stored as well
+				Config: strings.Replace(strings.Replace(config(`gas_price = "20 gwei"`), `signer = "`+faucetPk+`"`, `from = "`+strings.ToLower(faucetAddr.Hex())+`"`, 1),
+					"to = evm_random_pk.deployer.address", "to = lower(evm_random_pk.deployer.address)", 1),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("evm_transfer.fund", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckNoResourceAttr("evm_transfer.fund", "signer"),
+					resource.TestCheckResourceAttr("evm_transfer.fund", "from", strings.ToLower(faucetAddr.Hex())),
+					testCheckBalance("evm_random_pk.deployer", "address", big.NewInt(15*params.Ether/10-210000*params.GWei)),
+				),
+			},
+			{
+				// Signer resolving to another sender replaces the transfer
+				Config: strings.Replace(config(""), faucetPk, "0000000000000000000000000000000000000000000000000000000000000001", 1),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("evm_transfer.fund", plancheck.ResourceActionDestroyBeforeCreate),
+					},
+				},
+				ExpectError: regexp.MustCompile("Insufficient funds error"),
+			},
+			{
+				Config: `resource "evm_random_pk" "poor" {}
+
+				resource "evm_transfer" "overdraft" {
+					signer = evm_random_pk.poor.pk
+					to = "` + faucetAddr.Hex() + `"
+					value = "1 ether"
+				}
+				`,
+				ExpectError: regexp.MustCompile("Insufficient funds error"),
+			},
+		},
+	})
+}
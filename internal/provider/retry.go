@@ -0,0 +1,152 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"terraform-provider-evm/internal/utils"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	defaultRetryMaxAttempts = 5
+	defaultRetryBackoff     = time.Second
+	defaultRetryMaxBackoff  = 30 * time.Second
+	defaultRetryJitter      = 0.2
+)
+
+// retryPolicy bounds retries of node requests failed with retryable errors. Delay between attempts
+// grows exponentially from backoff up to maxBackoff and is randomized by the jitter fraction.
+type retryPolicy struct {
+	maxAttempts int
+	backoff     time.Duration
+	maxBackoff  time.Duration
+	jitter      float64
+}
+
+var defaultRetryPolicy = retryPolicy{
+	maxAttempts: defaultRetryMaxAttempts,
+	backoff:     defaultRetryBackoff,
+	maxBackoff:  defaultRetryMaxBackoff,
+	jitter:      defaultRetryJitter,
+}
+
+// providerRetryModel describes the provider retry block.
+type providerRetryModel struct {
+	MaxAttempts types.Int64   `tfsdk:"max_attempts"`
+	Backoff     types.String  `tfsdk:"backoff"`
+	MaxBackoff  types.String  `tfsdk:"max_backoff"`
+	Jitter      types.Float64 `tfsdk:"jitter"`
+}
+
+// policy converts the retry block to the policy, using defaults for the attributes which are not set or
+// unknown while planning.
+func (m *providerRetryModel) policy(attrPath path.Path, diags *diag.Diagnostics) retryPolicy {
+	policy := defaultRetryPolicy
+	if m == nil {
+		return policy
+	}
+
+	if !m.MaxAttempts.IsNull() && !m.MaxAttempts.IsUnknown() {
+		policy.maxAttempts = int(m.MaxAttempts.ValueInt64())
+		if policy.maxAttempts < 1 {
+			diags.AddAttributeError(attrPath.AtName("max_attempts"), "Invalid retry attempts", "At least one attempt is required")
+		}
+	}
+	if !m.Backoff.IsNull() && !m.Backoff.IsUnknown() {
+		policy.backoff = parseRetryDuration(attrPath.AtName("backoff"), m.Backoff.ValueString(), diags)
+	}
+	if !m.MaxBackoff.IsNull() && !m.MaxBackoff.IsUnknown() {
+		policy.maxBackoff = parseRetryDuration(attrPath.AtName("max_backoff"), m.MaxBackoff.ValueString(), diags)
+	}
+	if policy.maxBackoff < policy.backoff {
+		diags.AddAttributeError(
+			attrPath.AtName("max_backoff"),
+			"Invalid retry backoff",
+			fmt.Sprintf("Maximum backoff %v is less than the initial backoff %v", policy.maxBackoff, policy.backoff),
+		)
+	}
+	if !m.Jitter.IsNull() && !m.Jitter.IsUnknown() {
+		policy.jitter = m.Jitter.ValueFloat64()
+		if policy.jitter < 0 || policy.jitter > 1 {
+			diags.AddAttributeError(attrPath.AtName("jitter"), "Invalid retry jitter", "Jitter should be between 0 and 1")
+		}
+	}
+	return policy
+}
+
+func parseRetryDuration(attrPath path.Path, value string, diags *diag.Diagnostics) time.Duration {
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration < 0 {
+		diags.AddAttributeError(attrPath, "Invalid retry duration", fmt.Sprintf("Expected duration like `500ms` or `2s`, got '%s'", value))
+	}
+	return duration
+}
+
+// delay returns randomized delay before the given retry (starting from 1).
+func (p retryPolicy) delay(retry int) time.Duration {
+	delay := p.backoff
+	for i := 1; i < retry && delay < p.maxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > p.maxBackoff {
+		delay = p.maxBackoff
+	}
+	if p.jitter > 0 {
+		delay += time.Duration(float64(delay) * p.jitter * (2*rand.Float64() - 1))
+	}
+	return delay
+}
+
+// permanentError stops retries of the operation regardless of the cause, the wrapped error is returned
+// by the policy as is.
+type permanentError struct {
+	err error
+}
+
+func (e permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e permanentError) Unwrap() error {
+	return e.err
+}
+
+// do calls fn until it succeeds, fails with an error which is not retryable or attempts run out.
+// The last error is returned in the latter case.
+func (p retryPolicy) do(ctx context.Context, operation string, fn func() error) error {
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		var permanent permanentError
+		if errors.As(err, &permanent) {
+			return permanent.err
+		}
+		if err == nil || !utils.IsRetryableError(err) {
+			return err
+		}
+		if attempt >= p.maxAttempts {
+			return fmt.Errorf("%s failed after %d attempts: %w", operation, attempt, err)
+		}
+
+		delay := p.delay(attempt)
+		tflog.Warn(ctx, fmt.Sprintf("%s failed with retryable error, retrying", operation), map[string]interface{}{
+			"attempt":      attempt,
+			"max_attempts": p.maxAttempts,
+			"delay":        delay.String(),
+			"err":          err.Error(),
+		})
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryPolicy(t *testing.T) {
+	ctx := context.Background()
+	policy := retryPolicy{maxAttempts: 3, backoff: time.Millisecond, maxBackoff: 2 * time.Millisecond, jitter: 0.5}
+
+	// Retryable errors until success
+	calls := 0
+	err := policy.do(ctx, "Test", func() error {
+		calls++
+		if calls < 3 {
+			return rpc.HTTPError{StatusCode: 503, Status: "503 Service Unavailable"}
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, calls)
+
+	// Attempts run out, the last error is returned
+	calls = 0
+	err = policy.do(ctx, "Test", func() error {
+		calls++
+		return core.ErrNonceTooLow
+	})
+	assert.ErrorIs(t, err, core.ErrNonceTooLow)
+	assert.Equal(t, 3, calls)
+
+	// Fatal errors are not retried
+	calls = 0
+	fatal := errors.New("execution reverted")
+	err = policy.do(ctx, "Test", func() error {
+		calls++
+		return fatal
+	})
+	assert.Equal(t, fatal, err)
+	assert.Equal(t, 1, calls)
+}
+
+func TestRetryPolicyDelay(t *testing.T) {
+	policy := retryPolicy{maxAttempts: 10, backoff: time.Second, maxBackoff: 5 * time.Second}
+	assert.Equal(t, time.Second, policy.delay(1))
+	assert.Equal(t, 2*time.Second, policy.delay(2))
+	assert.Equal(t, 4*time.Second, policy.delay(3))
+	assert.Equal(t, 5*time.Second, policy.delay(4))
+	assert.Equal(t, 5*time.Second, policy.delay(40))
+
+	policy.jitter = 0.2
+	for i := 0; i < 100; i++ {
+		delay := policy.delay(1)
+		assert.GreaterOrEqual(t, delay, 800*time.Millisecond)
+		assert.LessOrEqual(t, delay, 1200*time.Millisecond)
+	}
+}
@@ -0,0 +1,351 @@
+package provider
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+
+	"terraform-provider-evm/internal/utils"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	impersonateAnvil   = "anvil"
+	impersonateHardhat = "hardhat"
+)
+
+// txSigner is a transaction signer resolved from resource or provider configuration.
+// Transactions are signed either locally with the private key, by the remote signer
+// or, for unlocked accounts, by the node itself.
+type txSigner struct {
+	address    common.Address
+	privateKey *ecdsa.PrivateKey
+	remote     *utils.RemoteSigner
+	unlocked   bool
+	// impersonate is the node flavour (anvil or hardhat) used to impersonate unlocked account
+	impersonate string
+}
+
+func newKeySigner(privateKey *ecdsa.PrivateKey) *txSigner {
+	return &txSigner{
+		address:    crypto.PubkeyToAddress(privateKey.PublicKey),
+		privateKey: privateKey,
+	}
+}
+
+// transactOpts returns options for bind calls which prepare transaction without sending it, see send.
+func (s *txSigner) transactOpts(ctx context.Context, chainID *big.Int) (*bind.TransactOpts, error) {
+	opts := &bind.TransactOpts{
+		From:    s.address,
+		Context: ctx,
+		NoSend:  true,
+	}
+
+	switch {
+	case s.remote != nil:
+		opts.Signer = func(address common.Address, tx *ethTypes.Transaction) (*ethTypes.Transaction, error) {
+			if address != s.address {
+				return nil, bind.ErrNotAuthorized
+			}
+			return s.remote.SignTx(ctx, tx, chainID)
+		}
+	case s.unlocked:
+		// Transaction is signed by the node on sending
+		opts.Signer = func(address common.Address, tx *ethTypes.Transaction) (*ethTypes.Transaction, error) {
+			return tx, nil
+		}
+	default:
+		keyedOpts, err := bind.NewKeyedTransactorWithChainID(s.privateKey, chainID)
+		if err != nil {
+			return nil, err
+		}
+		opts.Signer = keyedOpts.Signer
+	}
+
+	return opts, nil
+}
+
+// send broadcasts transaction prepared with transactOpts and returns its hash.
+// Transactions of unlocked accounts are sent with eth_sendTransaction for the node to sign them,
+// so their hash is only known after sending.
+func (s *txSigner) send(ctx context.Context, client EvmClient, chainID *big.Int, tx *ethTypes.Transaction) (common.Hash, error) {
+	if !s.unlocked {
+		return tx.Hash(), client.SendTransaction(ctx, tx)
+	}
+
+	if s.impersonate != "" {
+		if err := client.CallContext(ctx, nil, s.impersonate+"_impersonateAccount", s.address); err != nil {
+			return common.Hash{}, fmt.Errorf("cannot impersonate %s: %w", s.address.Hex(), err)
+		}
+		defer func() {
+			if err := client.CallContext(ctx, nil, s.impersonate+"_stopImpersonatingAccount", s.address); err != nil {
+				tflog.Warn(ctx, fmt.Sprintf("Cannot stop impersonating %s: %v", s.address.Hex(), err))
+			}
+		}()
+	}
+
+	var hash common.Hash
+	err := client.CallContext(ctx, &hash, "eth_sendTransaction", utils.NewTransactionArgs(s.address, tx, chainID))
+	return hash, err
+}
+
+// signerAttributes adds the attributes selecting a transaction signer to the resource schema.
+func signerAttributes(attributes map[string]schema.Attribute) map[string]schema.Attribute {
+	attributes["signer"] = schema.StringAttribute{
+		MarkdownDescription: "Transaction signer private key (32-byte hex, no `0x` prefix). Can reference `evm_random_pk.pk` resource. Conflicts with `keystore`, `signer_name` and `from`",
+		Optional:            true,
+		Sensitive:           true,
+	}
+	attributes["keystore"] = schema.StringAttribute{
+		MarkdownDescription: "Encrypted V3 keystore of the transaction signer, either JSON content or a path to the keystore file. Requires `keystore_password`, conflicts with `signer`, `signer_name` and `from`",
+		Optional:            true,
+		Sensitive:           true,
+	}
+	attributes["keystore_password"] = schema.StringAttribute{
+		MarkdownDescription: "Password used to decrypt `keystore`",
+		Optional:            true,
+		Sensitive:           true,
+	}
+	attributes["signer_name"] = schema.StringAttribute{
+		MarkdownDescription: "Name of the signer configured in the provider `signers` block. Conflicts with `signer`, `keystore` and `from`",
+		Optional:            true,
+	}
+	attributes["from"] = schema.StringAttribute{
+		MarkdownDescription: "Address of the account unlocked on the node (20-byte hex with `0x` prefix). Transaction is sent unsigned with `eth_sendTransaction` and signed by the node, which is mostly useful with development nodes. Conflicts with `signer`, `keystore` and `signer_name`. With other signers it is computed as the transaction sender address",
+		Optional:            true,
+		Computed:            true,
+	}
+	attributes["impersonate"] = schema.StringAttribute{
+		MarkdownDescription: "Impersonate `from` account before sending the transaction using development node API, either `anvil` or `hardhat`",
+		Optional:            true,
+	}
+	return attributes
+}
+
+// signerConfig holds signer related attributes shared by resource models.
+type signerConfig struct {
+	Signer           types.String
+	Keystore         types.String
+	KeystorePassword types.String
+	SignerName       types.String
+	From             types.String
+	Impersonate      types.String
+}
+
+// validate checks signer attributes at plan time, decrypting the keystore when all values are known.
+func (c signerConfig) validate(diags *diag.Diagnostics) {
+	if c.Signer.IsUnknown() || c.Keystore.IsUnknown() || c.SignerName.IsUnknown() || c.From.IsUnknown() {
+		return
+	}
+
+	configured := 0
+	for _, value := range []types.String{c.Signer, c.Keystore, c.SignerName, c.From} {
+		if !value.IsNull() {
+			configured++
+		}
+	}
+	if configured != 1 {
+		diags.AddAttributeError(
+			path.Root("signer"),
+			"Invalid signer configuration",
+			"Exactly one of `signer`, `keystore`, `signer_name` or `from` must be specified",
+		)
+		return
+	}
+
+	if !c.Impersonate.IsNull() && !c.Impersonate.IsUnknown() {
+		if c.From.IsNull() {
+			diags.AddAttributeError(path.Root("impersonate"), "Invalid signer configuration", "`impersonate` requires `from` account")
+		}
+		if impersonate := c.Impersonate.ValueString(); impersonate != impersonateAnvil && impersonate != impersonateHardhat {
+			diags.AddAttributeError(
+				path.Root("impersonate"),
+				"Invalid signer configuration",
+				fmt.Sprintf("Expected `%s` or `%s`, got `%s`", impersonateAnvil, impersonateHardhat, impersonate),
+			)
+		}
+	}
+
+	switch {
+	case !c.From.IsNull():
+		if !common.IsHexAddress(c.From.ValueString()) {
+			diags.AddAttributeError(path.Root("from"), "Invalid signer configuration", "`from` should be 20-byte hex address with `0x` prefix")
+		}
+	case !c.Signer.IsNull():
+		if _, err := crypto.HexToECDSA(c.Signer.ValueString()); err != nil {
+			diags.AddAttributeError(path.Root("signer"), "Error decoding signer to private key", err.Error())
+		}
+	case !c.Keystore.IsNull():
+		if c.KeystorePassword.IsNull() {
+			diags.AddAttributeError(
+				path.Root("keystore_password"),
+				"Missing keystore password",
+				"`keystore_password` is required when `keystore` is specified",
+			)
+			return
+		}
+		if c.KeystorePassword.IsUnknown() {
+			return
+		}
+		decryptKeystore(path.Empty(), c.Keystore.ValueString(), c.KeystorePassword.ValueString(), diags)
+	}
+}
+
+// resolve returns the signer selected by resource attributes, looking up named signers in the provider data.
+func (c signerConfig) resolve(data providerData, diags *diag.Diagnostics) *txSigner {
+	switch {
+	// Planned `from` is unknown when it is not configured, as it is computed from other signers
+	case !c.From.IsNull() && !c.From.IsUnknown():
+		return &txSigner{
+			address:     common.HexToAddress(c.From.ValueString()),
+			unlocked:    true,
+			impersonate: c.Impersonate.ValueString(),
+		}
+	case !c.SignerName.IsNull():
+		signer, ok := data.signers[c.SignerName.ValueString()]
+		if !ok {
+			diags.AddAttributeError(
+				path.Root("signer_name"),
+				"Unknown signer",
+				fmt.Sprintf("Signer '%s' is not configured in the provider `signers` block", c.SignerName.ValueString()),
+			)
+			return nil
+		}
+		return signer
+	case !c.Keystore.IsNull():
+		privateKey := decryptKeystore(path.Empty(), c.Keystore.ValueString(), c.KeystorePassword.ValueString(), diags)
+		if privateKey == nil {
+			return nil
+		}
+		return newKeySigner(privateKey)
+	default:
+		privateKey, err := crypto.HexToECDSA(c.Signer.ValueString())
+		if err != nil {
+			diags.AddAttributeError(path.Root("signer"), "Error decoding signer to private key", err.Error())
+			return nil
+		}
+		return newKeySigner(privateKey)
+	}
+}
+
+func (m providerSignerModel) hasUnknownValues() bool {
+	for _, value := range []types.String{m.Name, m.PrivateKey, m.Keystore, m.KeystorePassword, m.Env, m.RemoteUrl, m.RemoteMethod, m.Address} {
+		if value.IsUnknown() {
+			return true
+		}
+	}
+	return false
+}
+
+// resolve loads the key of a signer configured in the provider `signers` block.
+func (m providerSignerModel) resolve(ctx context.Context, attrPath path.Path, diags *diag.Diagnostics) *txSigner {
+	configured := 0
+	for _, value := range []types.String{m.PrivateKey, m.Keystore, m.Env, m.RemoteUrl} {
+		if !value.IsNull() {
+			configured++
+		}
+	}
+	if configured != 1 {
+		diags.AddAttributeError(
+			attrPath,
+			"Invalid signer configuration",
+			fmt.Sprintf("Signer '%s' requires exactly one of `private_key`, `keystore`, `env` or `remote_url`", m.Name.ValueString()),
+		)
+		return nil
+	}
+
+	switch {
+	case !m.RemoteUrl.IsNull():
+		if !common.IsHexAddress(m.Address.ValueString()) {
+			diags.AddAttributeError(
+				attrPath.AtName("address"),
+				"Invalid remote signer address",
+				fmt.Sprintf("Signer '%s' requires `address` of the remote account (20-byte hex with `0x` prefix)", m.Name.ValueString()),
+			)
+			return nil
+		}
+		address := common.HexToAddress(m.Address.ValueString())
+		method := utils.RemoteSignMethodEth
+		if !m.RemoteMethod.IsNull() {
+			method = m.RemoteMethod.ValueString()
+		}
+		client, err := rpc.DialContext(ctx, m.RemoteUrl.ValueString())
+		if err != nil {
+			diags.AddAttributeError(attrPath.AtName("remote_url"), "Unable to Create Remote Signer Client", err.Error())
+			return nil
+		}
+		remote, err := utils.NewRemoteSigner(client, method, address)
+		if err != nil {
+			diags.AddAttributeError(attrPath.AtName("remote_method"), "Invalid remote signer method", err.Error())
+			return nil
+		}
+		return &txSigner{address: address, remote: remote}
+	case !m.Keystore.IsNull():
+		if m.KeystorePassword.IsNull() {
+			diags.AddAttributeError(
+				attrPath.AtName("keystore_password"),
+				"Missing keystore password",
+				fmt.Sprintf("Signer '%s' requires `keystore_password` when `keystore` is specified", m.Name.ValueString()),
+			)
+			return nil
+		}
+		privateKey := decryptKeystore(attrPath, m.Keystore.ValueString(), m.KeystorePassword.ValueString(), diags)
+		if privateKey == nil {
+			return nil
+		}
+		return newKeySigner(privateKey)
+	case !m.Env.IsNull():
+		value, ok := os.LookupEnv(m.Env.ValueString())
+		if !ok {
+			diags.AddAttributeError(
+				attrPath.AtName("env"),
+				"Missing signer environment variable",
+				fmt.Sprintf("Environment variable '%s' for signer '%s' is not set", m.Env.ValueString(), m.Name.ValueString()),
+			)
+			return nil
+		}
+		privateKey, err := crypto.HexToECDSA(value)
+		if err != nil {
+			diags.AddAttributeError(
+				attrPath.AtName("env"),
+				"Error decoding signer to private key",
+				fmt.Sprintf("Environment variable '%s': %v", m.Env.ValueString(), err),
+			)
+			return nil
+		}
+		return newKeySigner(privateKey)
+	default:
+		privateKey, err := crypto.HexToECDSA(m.PrivateKey.ValueString())
+		if err != nil {
+			diags.AddAttributeError(attrPath.AtName("private_key"), "Error decoding signer to private key", err.Error())
+			return nil
+		}
+		return newKeySigner(privateKey)
+	}
+}
+
+func decryptKeystore(attrPath path.Path, keystore string, password string, diags *diag.Diagnostics) *ecdsa.PrivateKey {
+	privateKey, err := utils.DecryptKeystore(keystore, password)
+	if errors.Is(err, utils.ErrKeystoreWrongPassword) {
+		diags.AddAttributeError(attrPath.AtName("keystore_password"), "Wrong keystore password", err.Error())
+		return nil
+	}
+	if err != nil {
+		diags.AddAttributeError(attrPath.AtName("keystore"), "Error reading keystore", err.Error())
+		return nil
+	}
+	return privateKey
+}
@@ -0,0 +1,377 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"terraform-provider-evm/internal/utils"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/txpool"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// sendTransaction prepares transaction with build and sends it on behalf of the signer, returning the
+// transaction hash. Gas limit and fees are set according to fees and nonce is assigned by the provider
+// nonce manager. Transaction is prepared again with retryable errors only while the node certainly didn't
+// accept it, e.g. when the nonce is rejected, see broadcast.
+func (d providerData) sendTransaction(ctx context.Context, signer *txSigner, opts *bind.TransactOpts, chainID *big.Int, fees txFees,
+	build func(opts *bind.TransactOpts) (*ethTypes.Transaction, error)) (common.Hash, error) {
+	var txHash common.Hash
+	err := d.retry.do(ctx, "Preparing transaction", func() error {
+		return d.nonces.send(ctx, d.client, signer.address, func(nonce uint64) error {
+			opts.Nonce = new(big.Int).SetUint64(nonce)
+			if err := fees.apply(ctx, opts, build); err != nil {
+				return err
+			}
+			tx, err := build(opts)
+			if err != nil {
+				return err
+			}
+			txHash, err = d.broadcast(ctx, signer, chainID, tx)
+			return err
+		})
+	})
+	return txHash, err
+}
+
+// broadcast sends the prepared transaction, retrying failed requests with the same signed transaction. The node
+// may accept the transaction even though the request fails (e.g. with a timeout), so the transaction found in
+// the pool or on chain on the next attempt is sent successfully. Errors following such failures are permanent,
+// as preparing the transaction again with the next nonce could send it twice.
+func (d providerData) broadcast(ctx context.Context, signer *txSigner, chainID *big.Int, tx *ethTypes.Transaction) (common.Hash, error) {
+	var txHash common.Hash
+	// maybeSent is set once the request fails without the node rejecting the transaction
+	maybeSent := false
+	err := d.retry.do(ctx, "Sending transaction", func() error {
+		var err error
+		txHash, err = signer.send(ctx, d.client, chainID, tx)
+		switch {
+		case err == nil:
+			return nil
+		// Hash of the transaction signed by the node is not known, so it can't be looked up
+		case !signer.unlocked && isKnownTransaction(err):
+			txHash = tx.Hash()
+			return nil
+		case isNonceRejected(err) && maybeSent && !signer.unlocked && d.isTransactionSent(ctx, tx.Hash()):
+			txHash = tx.Hash()
+			return nil
+		case isNonceRejected(err) && maybeSent && signer.unlocked:
+			return permanentError{err}
+		case isNonceRejected(err):
+			// Nonce is used by another transaction, so the transaction is prepared again with the next nonce
+			maybeSent = false
+			return permanentError{err}
+		}
+		maybeSent = maybeSent || utils.IsRetryableError(err)
+		return err
+	})
+	if err != nil && maybeSent {
+		return txHash, permanentError{fmt.Errorf("transaction from %s with nonce %d may have been sent by the failed request, check the transactions of the account before applying again: %w", signer.address.Hex(), tx.Nonce(), err)}
+	}
+	return txHash, err
+}
+
+// isNonceRejected reports whether the node rejected the transaction as its nonce is used by another
+// transaction, either on chain or in the pool.
+func isNonceRejected(err error) bool {
+	message := err.Error()
+	return strings.Contains(message, core.ErrNonceTooLow.Error()) || strings.Contains(message, core.ErrNonceTooHigh.Error()) ||
+		strings.Contains(message, txpool.ErrReplaceUnderpriced.Error())
+}
+
+// isKnownTransaction reports whether the node rejected the transaction which is already in the pool.
+func isKnownTransaction(err error) bool {
+	message := strings.ToLower(err.Error())
+	return strings.Contains(message, "already known") || strings.Contains(message, "known transaction")
+}
+
+// isTransactionSent reports whether the transaction is in the pool or on chain.
+func (d providerData) isTransactionSent(ctx context.Context, hash common.Hash) bool {
+	_, _, err := d.client.TransactionByHash(ctx, hash)
+	return err == nil
+}
+
+// buildTransfer returns build function of the value transfer to the account. Bound contract only fills
+// fees and signs the transaction, gas is estimated here as bind refuses to estimate calls to accounts
+// without code.
+func buildTransfer(ctx context.Context, client EvmClient, to common.Address) func(opts *bind.TransactOpts) (*ethTypes.Transaction, error) {
+	c := bind.NewBoundContract(to, abi.ABI{}, client, client, client)
+	return func(opts *bind.TransactOpts) (*ethTypes.Transaction, error) {
+		transferOpts := *opts
+		if transferOpts.GasLimit == 0 {
+			gasLimit, err := client.EstimateGas(ctx, ethereum.CallMsg{From: opts.From, To: &to, Value: opts.Value})
+			if err != nil {
+				return nil, fmt.Errorf("failed to estimate gas needed: %w", err)
+			}
+			transferOpts.GasLimit = gasLimit
+		}
+		return c.RawTransact(&transferOpts, nil)
+	}
+}
+
+// buildDeploy returns build function of the contract creation transaction with the init code.
+func buildDeploy(client EvmClient, initCode []byte) func(opts *bind.TransactOpts) (*ethTypes.Transaction, error) {
+	return func(opts *bind.TransactOpts) (*ethTypes.Transaction, error) {
+		_, tx, _, err := bind.DeployContract(opts, abi.ABI{}, initCode, client)
+		return tx, err
+	}
+}
+
+// sendRawTransaction broadcasts the transaction signed outside of the provider as is, e.g. presigned
+// transaction which nonce is not managed by the provider.
+func (d providerData) sendRawTransaction(ctx context.Context, rawTx []byte) (common.Hash, error) {
+	tx := new(ethTypes.Transaction)
+	if err := tx.UnmarshalBinary(rawTx); err != nil {
+		return common.Hash{}, fmt.Errorf("cannot decode raw transaction: %w", err)
+	}
+	err := d.retry.do(ctx, "Sending raw transaction", func() error {
+		err := d.client.SendTransaction(ctx, tx)
+		// Transaction sent by the failed attempt is already in the pool
+		if err != nil && isKnownTransaction(err) {
+			return nil
+		}
+		return err
+	})
+	return tx.Hash(), err
+}
+
+// parseValue converts value attribute in wei or with a unit suffix to wei, null value is zero.
+func parseValue(value types.String, diags *diag.Diagnostics) *big.Int {
+	if value.IsNull() || value.IsUnknown() {
+		return new(big.Int)
+	}
+	wei, err := utils.ParseWei(value.ValueString())
+	if err != nil {
+		diags.AddAttributeError(path.Root("value"), "Invalid value", err.Error())
+		return nil
+	}
+	return wei
+}
+
+// txCall describes the transaction sent by a resource, used to explain its failure.
+type txCall struct {
+	msg    ethereum.CallMsg
+	target string
+	method string
+	// abis declare custom errors the transaction can revert with
+	abis []*abi.ABI
+}
+
+// revertReason decodes revert data of the transaction failed on sending. bind converts gas estimation
+// errors to text, so unless the error carries the data, the call is replayed to recover it.
+func (c txCall) revertReason(ctx context.Context, client EvmClient, err error) (string, bool) {
+	if data, ok := utils.RevertData(err); ok {
+		return utils.DecodeRevert(data, c.abis...), true
+	}
+	if !strings.Contains(err.Error(), vm.ErrExecutionReverted.Error()) {
+		return "", false
+	}
+	return c.replay(ctx, client, nil)
+}
+
+// replay executes the call with eth_call at the given block (latest when nil) and decodes the revert reason.
+func (c txCall) replay(ctx context.Context, client EvmClient, blockNumber *big.Int) (string, bool) {
+	_, err := client.CallContract(ctx, c.msg, blockNumber)
+	if err == nil || !strings.Contains(err.Error(), vm.ErrExecutionReverted.Error()) {
+		return "", false
+	}
+	data, _ := utils.RevertData(err)
+	return utils.DecodeRevert(data, c.abis...), true
+}
+
+// details describes the failed transaction in diagnostics.
+func (c txCall) details(reason string) string {
+	return fmt.Sprintf("Signer %s\nTarget %s\nMethod %s\nReason %s", c.msg.From.Hex(), c.target, c.method, reason)
+}
+
+// checkReceipt adds error diagnostic when the transaction is mined with failed status, replaying
+// the call at the block it was mined in to recover the revert reason.
+func (c txCall) checkReceipt(ctx context.Context, client EvmClient, receipt *ethTypes.Receipt, diags *diag.Diagnostics) {
+	if receipt.Status == ethTypes.ReceiptStatusSuccessful {
+		return
+	}
+
+	reason, ok := c.replay(ctx, client, receipt.BlockNumber)
+	if !ok {
+		reason = fmt.Sprintf("unknown, the call does not revert when replayed (%d gas used, possibly out of gas)", receipt.GasUsed)
+	}
+	diags.AddError(
+		"Transaction failed",
+		fmt.Sprintf("%s\nTransaction %s mined in block %v", c.details(reason), receipt.TxHash.Hex(), receipt.BlockNumber),
+	)
+}
+
+// deployment is the contract creation transaction sent by deploy.
+type deployment struct {
+	txHash  common.Hash
+	receipt *ethTypes.Receipt
+	address common.Address
+	code    []byte
+}
+
+// deploy sends the contract creation transaction prepared with build and waits until it's mined, checking that
+// the contract has code. The contract is expected at the address from the receipt, unless it's created by
+// the factory called with call.msg.To at created. Send errors are reported as failures of the call, unless
+// sendError (when set) reports them first. The deployment is returned as far as it got, so contracts
+// deployed on chain are recorded even when deploy fails afterwards.
+func (d providerData) deploy(ctx context.Context, signer *txSigner, opts *bind.TransactOpts, chainID *big.Int, fees txFees,
+	call txCall, build func(opts *bind.TransactOpts) (*ethTypes.Transaction, error), created common.Address,
+	sendError func(err error) bool, diags *diag.Diagnostics) deployment {
+	var result deployment
+	txHash, err := d.sendTransaction(ctx, signer, opts, chainID, fees, build)
+	if err != nil {
+		if sendError != nil && sendError(err) {
+			return result
+		}
+		utils.ParseNodeError(signer.address.Hex(), opts.Value, err, diags)
+		if diags.HasError() {
+			return result
+		}
+
+		if reason, ok := call.revertReason(ctx, d.client, err); ok {
+			diags.AddError("Deploy reverted", call.details(reason))
+			return result
+		}
+
+		diags.AddError("Deploy error", fmt.Sprintf("Signer %s\nCannot deploy %s: %v", signer.address.Hex(), call.target, err))
+		return result
+	}
+	result.txHash = txHash
+
+	// Wait until transaction is mined
+	receipt, err := waitMined(ctx, d.client, txHash)
+	if err != nil {
+		diags.AddError("Error while waiting for transaction to be mined", err.Error())
+		return result
+	}
+	result.receipt = receipt
+
+	call.checkReceipt(ctx, d.client, receipt, diags)
+	if diags.HasError() {
+		return result
+	}
+
+	result.address = receipt.ContractAddress
+	if call.msg.To != nil {
+		result.address = created
+	}
+	result.code, err = d.client.CodeAt(ctx, result.address, receipt.BlockNumber)
+	if err != nil {
+		diags.AddError("Cannot retrieve deployed code", err.Error())
+	} else if len(result.code) == 0 {
+		diags.AddError(
+			"Deploy error",
+			fmt.Sprintf("Signer %s\nTransaction %s succeeded, but there is no code at %s. Constructor of the artifact returned empty runtime code", signer.address.Hex(), txHash.Hex(), result.address.Hex()),
+		)
+	}
+	return result
+}
+
+// receiptFee returns fee paid for the mined transaction in wei, or nil when the node doesn't report
+// the effective gas price of the transaction.
+func receiptFee(receipt *ethTypes.Receipt) *big.Int {
+	if receipt.EffectiveGasPrice == nil {
+		return nil
+	}
+	return new(big.Int).Mul(new(big.Int).SetUint64(receipt.GasUsed), receipt.EffectiveGasPrice)
+}
+
+// waitMined waits for the transaction to be mined. Unlike bind.WaitMined it accepts the hash,
+// as transactions signed by the node are not available locally.
+func waitMined(ctx context.Context, client EvmClient, hash common.Hash) (*ethTypes.Receipt, error) {
+	queryTicker := time.NewTicker(time.Second)
+	defer queryTicker.Stop()
+
+	for {
+		receipt, err := client.TransactionReceipt(ctx, hash)
+		if err == nil {
+			return receipt, nil
+		}
+
+		if errors.Is(err, ethereum.NotFound) {
+			tflog.Trace(ctx, "Transaction not yet mined")
+		} else {
+			tflog.Trace(ctx, "Receipt retrieval failed", map[string]interface{}{"err": err})
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-queryTicker.C:
+		}
+	}
+}
+
+// receiptAttributes adds computed attributes describing the mined transaction to the resource schema.
+// The sender address is set to the `from` attribute of signerAttributes.
+func receiptAttributes(attributes map[string]schema.Attribute) map[string]schema.Attribute {
+	attributes["block_number"] = schema.Int64Attribute{
+		MarkdownDescription: "Number of the block including the transaction",
+		Computed:            true,
+	}
+	attributes["block_hash"] = schema.StringAttribute{
+		MarkdownDescription: "Hash of the block including the transaction",
+		Computed:            true,
+	}
+	attributes["gas_used"] = schema.Int64Attribute{
+		MarkdownDescription: "Gas used by the transaction",
+		Computed:            true,
+	}
+	attributes["effective_gas_price"] = schema.StringAttribute{
+		MarkdownDescription: "Gas price paid by the transaction in wei, which for EIP-1559 transactions is the base fee plus the priority fee. Null when the node doesn't report it in the receipt",
+		Computed:            true,
+	}
+	attributes["fee_paid_wei"] = schema.StringAttribute{
+		MarkdownDescription: "Transaction fee paid by the signer in wei, calculated as gas used multiplied by the effective gas price. Null when the effective gas price is not reported",
+		Computed:            true,
+	}
+	attributes["nonce"] = schema.Int64Attribute{
+		MarkdownDescription: "Nonce of the transaction",
+		Computed:            true,
+	}
+	return attributes
+}
+
+// txReceipt holds values of the receipt attributes.
+type txReceipt struct {
+	BlockNumber       types.Int64
+	BlockHash         types.String
+	GasUsed           types.Int64
+	EffectiveGasPrice types.String
+	FeePaidWei        types.String
+	Nonce             types.Int64
+	From              types.String
+}
+
+// newTxReceipt converts receipt of the transaction sent with the nonce by the signer to attribute values.
+func newTxReceipt(receipt *ethTypes.Receipt, nonce uint64, from common.Address) txReceipt {
+	effectiveGasPrice, feePaid := types.StringNull(), types.StringNull()
+	if fee := receiptFee(receipt); fee != nil {
+		effectiveGasPrice = types.StringValue(receipt.EffectiveGasPrice.String())
+		feePaid = types.StringValue(fee.String())
+	}
+	return txReceipt{
+		BlockNumber:       types.Int64Value(receipt.BlockNumber.Int64()),
+		BlockHash:         types.StringValue(receipt.BlockHash.Hex()),
+		GasUsed:           types.Int64Value(int64(receipt.GasUsed)),
+		EffectiveGasPrice: effectiveGasPrice,
+		FeePaidWei:        feePaid,
+		Nonce:             types.Int64Value(int64(nonce)),
+		From:              types.StringValue(from.Hex()),
+	}
+}
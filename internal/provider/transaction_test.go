@@ -0,0 +1,97 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+// lostResponseClient sends the first transaction to the node, but fails the request with err as if the
+// response was lost.
+type lostResponseClient struct {
+	SimulatedClient
+	err   error
+	sends int
+}
+
+// SendTransaction implements EvmClient.
+func (c *lostResponseClient) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	c.sends++
+	if err := c.SimulatedClient.SendTransaction(ctx, tx); err != nil {
+		return err
+	}
+	if c.sends == 1 {
+		return c.err
+	}
+	return nil
+}
+
+func TestSendTransactionResend(t *testing.T) {
+	ctx := context.Background()
+	privateKey, err := crypto.HexToECDSA(faucetPk)
+	if err != nil {
+		t.Fatalf("Cannot parse faucet key: %v", err)
+	}
+	chainID, _ := testClient.ChainID(ctx)
+	gasPrice, _ := testClient.SuggestGasPrice(ctx)
+	recipient := common.HexToAddress("0x000000000000000000000000000000000000dead")
+
+	for name, test := range map[string]struct {
+		err   error
+		sends int
+	}{
+		// Transaction is mined, so the node rejects its nonce on the next attempt
+		"timeout": {err: errors.New("request timed out"), sends: 2},
+		// Node reports the transaction in the pool
+		"already known": {err: errors.New("already known"), sends: 1},
+	} {
+		t.Run(name, func(t *testing.T) {
+			client := &lostResponseClient{SimulatedClient: testClient, err: test.err}
+			d := providerData{
+				client: client,
+				nonces: newNonceManager(),
+				retry:  retryPolicy{maxAttempts: 3, backoff: time.Millisecond, maxBackoff: time.Millisecond},
+			}
+			start, _ := testClient.PendingNonceAt(ctx, faucetAddr)
+			signer := newKeySigner(privateKey)
+			opts, _ := signer.transactOpts(ctx, chainID)
+			opts.Value = big.NewInt(1)
+
+			txHash, err := d.sendTransaction(ctx, signer, opts, chainID, txFees{gasLimit: 21000, gasPrice: gasPrice},
+				buildTransfer(ctx, client, recipient))
+			assert.NoError(t, err)
+
+			// The transaction is sent once, retries resend it with the same hash
+			assert.Equal(t, test.sends, client.sends)
+			tx, _, err := testClient.TransactionByHash(ctx, txHash)
+			assert.NoError(t, err)
+			assert.Equal(t, start, tx.Nonce())
+			pending, _ := testClient.PendingNonceAt(ctx, faucetAddr)
+			assert.Equal(t, start+1, pending)
+		})
+	}
+}
+
+func TestNewTxReceipt(t *testing.T) {
+	from := common.HexToAddress("0x000000000000000000000000000000000000dead")
+	receipt := &types.Receipt{BlockNumber: big.NewInt(1), GasUsed: 21000, EffectiveGasPrice: big.NewInt(10)}
+
+	txReceipt := newTxReceipt(receipt, 2, from)
+	assert.Equal(t, "10", txReceipt.EffectiveGasPrice.ValueString())
+	assert.Equal(t, "210000", txReceipt.FeePaidWei.ValueString())
+	assert.Equal(t, int64(2), txReceipt.Nonce.ValueInt64())
+
+	// Effective gas price is not reported by the node
+	receipt.EffectiveGasPrice = nil
+	txReceipt = newTxReceipt(receipt, 2, from)
+	assert.True(t, txReceipt.EffectiveGasPrice.IsNull())
+	assert.True(t, txReceipt.FeePaidWei.IsNull())
+	assert.Equal(t, int64(21000), txReceipt.GasUsed.ValueInt64())
+}
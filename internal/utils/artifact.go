@@ -3,8 +3,12 @@ package utils
 import (
 	"encoding/hex"
 	"errors"
+	"fmt"
+	"sort"
 	"strings"
 
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/tidwall/gjson"
 )
 
@@ -13,46 +17,462 @@ var (
 	ErrArtifactWrongFieldFormat = errors.New("wrong field format")
 )
 
-func GetBytecode(artifactJson string) ([]byte, error) {
-	value := gjson.Get(artifactJson, "bytecode")
-	if !value.Exists() {
+// GetConstructorArgTypes returns types of constructor arguments, which is an empty list for
+// contracts without explicit constructor in the ABI.
+func GetConstructorArgTypes(artifactJson string) ([]string, error) {
+	if !gjson.Get(artifactJson, "abi").IsArray() {
 		return nil, ErrArtifactFieldNotFound
 	}
 
-	valueStr := value.String()
-	if !strings.HasPrefix(valueStr, "0x") {
-		return nil, ErrArtifactWrongFieldFormat
+	value := gjson.Get(artifactJson, "abi.#(type==\"constructor\").inputs.#.type")
+	if !value.Exists() {
+		return []string{}, nil
+	}
+
+	valueArray := value.Array()
+
+	args := make([]string, len(valueArray))
+	for i := range valueArray {
+		args[i] = valueArray[i].String()
+	}
+
+	return args, nil
+}
+
+// ArtifactFormat is the layout of the compiled contract artifact.
+type ArtifactFormat string
+
+const (
+	// ArtifactFormatHardhat is the artifact written by Hardhat to `artifacts/`.
+	ArtifactFormatHardhat ArtifactFormat = "hardhat"
+	// ArtifactFormatFoundry is the artifact written by Foundry to `out/`.
+	ArtifactFormatFoundry ArtifactFormat = "foundry"
+	// ArtifactFormatTruffle is the artifact written by Truffle to `build/contracts/`.
+	ArtifactFormatTruffle ArtifactFormat = "truffle"
+	// ArtifactFormatSolc is the output of `solc --combined-json abi,bin,bin-runtime`.
+	ArtifactFormatSolc ArtifactFormat = "solc"
+	// ArtifactFormatVyper is the output of `vyper -f combined_json`.
+	ArtifactFormatVyper ArtifactFormat = "vyper"
+)
+
+// ArtifactFormats lists the supported artifact formats.
+var ArtifactFormats = []ArtifactFormat{
+	ArtifactFormatHardhat,
+	ArtifactFormatFoundry,
+	ArtifactFormatTruffle,
+	ArtifactFormatSolc,
+	ArtifactFormatVyper,
+}
+
+var ErrArtifactUnknownFormat = errors.New("unknown artifact format")
+
+// ParseArtifactFormat checks that the format is supported, empty format means it's detected from the artifact.
+func ParseArtifactFormat(format string) (ArtifactFormat, error) {
+	if format == "" {
+		return "", nil
+	}
+	for _, supported := range ArtifactFormats {
+		if ArtifactFormat(format) == supported {
+			return supported, nil
+		}
+	}
+	return "", errors.Join(ErrArtifactUnknownFormat, fmt.Errorf("'%s' is not one of %v", format, ArtifactFormats))
+}
+
+// LinkReference is the placeholder of the library address in the unlinked bytecode.
+type LinkReference struct {
+	// Source is the file declaring the library, which is empty when the artifact doesn't specify it
+	Source string
+	Name   string
+	Start  int
+	Length int
+}
+
+// Artifact is the compiled contract normalized from any of the supported artifact formats. Library
+// placeholders of the unlinked bytecode are zeroed and listed with the link references.
+type Artifact struct {
+	Format                 ArtifactFormat
+	Abi                    string
+	Bytecode               []byte
+	DeployedBytecode       []byte
+	LinkReferences         []LinkReference
+	DeployedLinkReferences []LinkReference
+	ImmutableReferences    []CodeRange
+}
+
+// ConstructorArgTypes returns types of constructor arguments, which is an empty list for
+// contracts without explicit constructor in the ABI.
+func (a *Artifact) ConstructorArgTypes() ([]string, error) {
+	return GetConstructorArgTypes(`{"abi":` + a.Abi + `}`)
+}
+
+// DetectArtifactFormat detects the format by the fields specific to each of the formats.
+func DetectArtifactFormat(artifactJson string) (ArtifactFormat, error) {
+	if !gjson.Valid(artifactJson) {
+		return "", errors.Join(ErrArtifactWrongFieldFormat, errors.New("artifact is not valid JSON"))
+	}
+	artifact := gjson.Parse(artifactJson)
+	switch {
+	case strings.HasPrefix(artifact.Get("_format").String(), "hh-sol-artifact"):
+		return ArtifactFormatHardhat, nil
+	case artifact.Get("bytecode.object").Exists():
+		return ArtifactFormatFoundry, nil
+	case artifact.Get("contracts").IsObject():
+		return ArtifactFormatSolc, nil
+	case artifact.Get("bytecode_runtime").Exists() || len(vyperContracts(artifact)) > 0:
+		return ArtifactFormatVyper, nil
+	case artifact.Get("schemaVersion").Exists() || artifact.Get("sourcePath").Exists():
+		return ArtifactFormatTruffle, nil
+	case artifact.Get("bytecode").Exists():
+		// Artifacts of other tools mostly follow the Hardhat layout
+		return ArtifactFormatHardhat, nil
+	}
+	return "", errors.Join(ErrArtifactUnknownFormat, errors.New("artifact has none of the fields of Hardhat, Foundry, Truffle, solc or Vyper artifacts"))
+}
+
+// ParseArtifact parses the artifact of the given format, which is detected when the format is empty. Contract
+// selects one of the contracts of the solc or Vyper combined output by the key, e.g. `src/Token.sol:Token`
+// for solc or `src/Token.vy` for Vyper, and may be empty when the output has a single contract with bytecode.
+func ParseArtifact(artifactJson string, format ArtifactFormat, contract string) (*Artifact, error) {
+	if format == "" {
+		var err error
+		if format, err = DetectArtifactFormat(artifactJson); err != nil {
+			return nil, err
+		}
+	}
+	if contract != "" && format != ArtifactFormatSolc && format != ArtifactFormatVyper {
+		return nil, fmt.Errorf("contract can only be selected in solc and Vyper artifacts, %s artifact has a single contract", format)
+	}
+
+	artifact := gjson.Parse(artifactJson)
+	var result *Artifact
+	var err error
+	switch format {
+	case ArtifactFormatHardhat:
+		result, err = parseHardhatArtifact(artifact)
+	case ArtifactFormatFoundry:
+		result, err = parseFoundryArtifact(artifact)
+	case ArtifactFormatTruffle:
+		result, err = parseTruffleArtifact(artifact)
+	case ArtifactFormatSolc:
+		result, err = parseSolcArtifact(artifact, contract)
+	case ArtifactFormatVyper:
+		result, err = parseVyperArtifact(artifact, contract)
+	default:
+		return nil, errors.Join(ErrArtifactUnknownFormat, fmt.Errorf("'%s' is not one of %v", format, ArtifactFormats))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s artifact: %w", format, err)
+	}
+	result.Format = format
+	return result, nil
+}
+
+func parseHardhatArtifact(artifact gjson.Result) (*Artifact, error) {
+	result := &Artifact{
+		LinkReferences:         parseLinkReferences(artifact.Get("linkReferences")),
+		DeployedLinkReferences: parseLinkReferences(artifact.Get("deployedLinkReferences")),
+	}
+	err := result.setFields(artifact.Get("abi"), artifact.Get("bytecode"), artifact.Get("deployedBytecode"), true)
+	return result, err
+}
+
+func parseFoundryArtifact(artifact gjson.Result) (*Artifact, error) {
+	result := &Artifact{
+		LinkReferences:         parseLinkReferences(artifact.Get("bytecode.linkReferences")),
+		DeployedLinkReferences: parseLinkReferences(artifact.Get("deployedBytecode.linkReferences")),
+		ImmutableReferences:    GetImmutableReferences(artifact.Raw),
+	}
+	err := result.setFields(artifact.Get("abi"), artifact.Get("bytecode.object"), artifact.Get("deployedBytecode.object"), true)
+	return result, err
+}
+
+func parseTruffleArtifact(artifact gjson.Result) (*Artifact, error) {
+	result := &Artifact{}
+	if err := result.setFields(artifact.Get("abi"), artifact.Get("bytecode"), artifact.Get("deployedBytecode"), true); err != nil {
+		return nil, err
+	}
+	var err error
+	if result.LinkReferences, err = findLinkReferences(artifact.Get("bytecode").String(), nil); err != nil {
+		return nil, err
+	}
+	if result.DeployedLinkReferences, err = findLinkReferences(artifact.Get("deployedBytecode").String(), nil); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func parseSolcArtifact(artifact gjson.Result, name string) (*Artifact, error) {
+	// Placeholders of solc 0.5+ are hashes of the fully qualified library names
+	libraries := map[string]string{}
+	contracts := map[string]gjson.Result{}
+	artifact.Get("contracts").ForEach(func(name, value gjson.Result) bool {
+		libraries[crypto.Keccak256Hash([]byte(name.String())).Hex()[2:36]] = name.String()
+		// Interfaces and abstract contracts don't have bytecode
+		if value.Get("bin").String() != "" {
+			contracts[name.String()] = value
+		}
+		return true
+	})
+	contract, err := selectContract(contracts, name)
+	if err != nil {
+		return nil, err
+	}
+
+	// ABI is encoded as a string by solc versions before 0.8.10
+	abiValue := contract.Get("abi")
+	if abiValue.Type == gjson.String {
+		abiValue = gjson.Parse(abiValue.String())
+	}
+	result := &Artifact{}
+	if err := result.setFields(abiValue, contract.Get("bin"), contract.Get("bin-runtime"), false); err != nil {
+		return nil, err
+	}
+	if result.LinkReferences, err = findLinkReferences(contract.Get("bin").String(), libraries); err != nil {
+		return nil, err
+	}
+	if result.DeployedLinkReferences, err = findLinkReferences(contract.Get("bin-runtime").String(), libraries); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func parseVyperArtifact(artifact gjson.Result, name string) (*Artifact, error) {
+	contract := artifact
+	if !artifact.Get("bytecode_runtime").Exists() {
+		var err error
+		if contract, err = selectContract(vyperContracts(artifact), name); err != nil {
+			return nil, err
+		}
+	} else if name != "" {
+		return nil, fmt.Errorf("contract %s can't be selected, artifact has a single contract", name)
 	}
 
-	bytecode, err := hex.DecodeString(valueStr[2:])
+	result := &Artifact{}
+	err := result.setFields(contract.Get("abi"), contract.Get("bytecode"), contract.Get("bytecode_runtime"), true)
+	return result, err
+}
+
+// selectContract selects the contract of the combined output by the name, or the single contract when
+// the name is empty.
+func selectContract(contracts map[string]gjson.Result, name string) (gjson.Result, error) {
+	names := make([]string, 0, len(contracts))
+	for contractName := range contracts {
+		names = append(names, contractName)
+	}
+	sort.Strings(names)
+
+	if name != "" {
+		contract, ok := contracts[name]
+		if !ok {
+			return gjson.Result{}, fmt.Errorf("%w: contract %s with bytecode, found %v", ErrArtifactFieldNotFound, name, names)
+		}
+		return contract, nil
+	}
+	if len(contracts) != 1 {
+		return gjson.Result{}, fmt.Errorf("expected a single contract with bytecode, found %d %v", len(names), names)
+	}
+	return contracts[names[0]], nil
+}
+
+// vyperContracts returns contracts of the Vyper combined JSON by the source file.
+func vyperContracts(artifact gjson.Result) map[string]gjson.Result {
+	contracts := map[string]gjson.Result{}
+	artifact.ForEach(func(name, value gjson.Result) bool {
+		if value.Get("bytecode_runtime").Exists() {
+			contracts[name.String()] = value
+		}
+		return true
+	})
+	return contracts
+}
+
+func (a *Artifact) setFields(abiValue, bytecode, deployedBytecode gjson.Result, prefixed bool) error {
+	if !abiValue.IsArray() {
+		return fmt.Errorf("abi: %w", ErrArtifactFieldNotFound)
+	}
+	a.Abi = abiValue.Raw
+
+	if !bytecode.Exists() {
+		return fmt.Errorf("bytecode: %w", ErrArtifactFieldNotFound)
+	}
+	var err error
+	if a.Bytecode, err = decodeUnlinkedCode(bytecode.String(), prefixed); err != nil {
+		return fmt.Errorf("bytecode: %w", err)
+	}
+	// Runtime bytecode is optional, as it's not used to deploy the contract
+	if a.DeployedBytecode, err = decodeUnlinkedCode(deployedBytecode.String(), prefixed && deployedBytecode.Exists()); err != nil {
+		return fmt.Errorf("runtime bytecode: %w", err)
+	}
+	return nil
+}
+
+// placeholderLength is the length of the library placeholder in the hex encoded bytecode, which is
+// replaced with the library address.
+const placeholderLength = 40
+
+// decodeUnlinkedCode decodes hex encoded bytecode with library placeholders replaced by zero addresses.
+func decodeUnlinkedCode(code string, prefixed bool) ([]byte, error) {
+	if prefixed {
+		if !strings.HasPrefix(code, "0x") {
+			return nil, ErrArtifactWrongFieldFormat
+		}
+		code = code[2:]
+	} else {
+		code = strings.TrimPrefix(code, "0x")
+	}
+
+	hexCode := []byte(code)
+	for i := 0; i < len(hexCode); i += 2 {
+		if hexCode[i] == '_' && i+placeholderLength <= len(hexCode) {
+			copy(hexCode[i:i+placeholderLength], strings.Repeat("0", placeholderLength))
+			i += placeholderLength - 2
+		}
+	}
+	bytecode, err := hex.DecodeString(string(hexCode))
 	if err != nil {
 		return nil, errors.Join(err, ErrArtifactWrongFieldFormat)
 	}
 	return bytecode, nil
 }
 
-func GetConstructorArgTypes(artifactJson string) ([]string, error) {
+// findLinkReferences finds library placeholders in the hex encoded bytecode. Placeholders are either
+// `__$<hash>$__` of solc 0.5+, which are resolved to the library name with the libraries by the hash, or
+// the library name padded with underscores of Truffle and earlier solc versions.
+func findLinkReferences(code string, libraries map[string]string) ([]LinkReference, error) {
+	code = strings.TrimPrefix(code, "0x")
+	var references []LinkReference
+	for i := 0; i+placeholderLength <= len(code); i += 2 {
+		if code[i] != '_' {
+			continue
+		}
+		placeholder := code[i : i+placeholderLength]
+		name := strings.Trim(placeholder, "_")
+		if strings.HasPrefix(placeholder, "__$") && strings.HasSuffix(placeholder, "$__") {
+			var ok bool
+			if name, ok = libraries[placeholder[3:37]]; !ok {
+				return nil, fmt.Errorf("unknown library of placeholder %s", placeholder)
+			}
+		}
 
-	value := gjson.Get(artifactJson, "abi.#(type==\"constructor\").inputs.#.type")
-	if !value.Exists() {
-		return nil, ErrArtifactFieldNotFound
+		reference := LinkReference{Name: name, Start: i / 2, Length: placeholderLength / 2}
+		if separator := strings.LastIndex(name, ":"); separator >= 0 {
+			reference.Source, reference.Name = name[:separator], name[separator+1:]
+		}
+		references = append(references, reference)
+		i += placeholderLength - 2
 	}
+	return references, nil
+}
 
-	valueArray := value.Array()
+// parseLinkReferences parses link references listed by the source file and library name.
+func parseLinkReferences(value gjson.Result) []LinkReference {
+	var references []LinkReference
+	value.ForEach(func(source, libraries gjson.Result) bool {
+		libraries.ForEach(func(name, ranges gjson.Result) bool {
+			for _, codeRange := range ranges.Array() {
+				references = append(references, LinkReference{
+					Source: source.String(),
+					Name:   name.String(),
+					Start:  int(codeRange.Get("start").Int()),
+					Length: int(codeRange.Get("length").Int()),
+				})
+			}
+			return true
+		})
+		return true
+	})
+	sort.Slice(references, func(i, j int) bool { return references[i].Start < references[j].Start })
+	return references
+}
 
-	args := make([]string, len(valueArray))
-	for i := range valueArray {
-		args[i] = valueArray[i].String()
+var (
+	ErrLibraryMissing = errors.New("missing library")
+	ErrLibraryUnused  = errors.New("unused library")
+)
+
+// LibraryName returns the fully qualified name of the library, e.g. `contracts/Math.sol:Math`, or
+// only the library name when the artifact doesn't specify the source file.
+func (r LinkReference) LibraryName() string {
+	if r.Source == "" {
+		return r.Name
 	}
+	return r.Source + ":" + r.Name
+}
 
-	return args, nil
+// Libraries returns sorted names of the libraries referenced by the creation bytecode.
+func (a *Artifact) Libraries() []string {
+	var names []string
+	seen := map[string]bool{}
+	for _, reference := range a.LinkReferences {
+		if name := reference.LibraryName(); !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
 }
 
-func GetAbi(artifactJson string) (string, error) {
-	value := gjson.Get(artifactJson, "abi")
-	if !value.Exists() {
-		return "", ErrArtifactFieldNotFound
+// CheckLibraries checks that the libraries by the fully qualified name are exactly the libraries
+// referenced by the artifact.
+func (a *Artifact) CheckLibraries(libraries []string) error {
+	referenced := a.Libraries()
+	var errs []error
+	for _, name := range referenced {
+		if !contains(libraries, name) {
+			errs = append(errs, fmt.Errorf("%w %s", ErrLibraryMissing, name))
+		}
+	}
+	unused := append([]string(nil), libraries...)
+	sort.Strings(unused)
+	for _, name := range unused {
+		if !contains(referenced, name) {
+			errs = append(errs, fmt.Errorf("%w %s, referenced libraries are %v", ErrLibraryUnused, name, referenced))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// LinkBytecode returns the creation bytecode with addresses of the libraries by the fully qualified
+// name filled in at the link references.
+func (a *Artifact) LinkBytecode(libraries map[string]common.Address) ([]byte, error) {
+	names := make([]string, 0, len(libraries))
+	for name := range libraries {
+		names = append(names, name)
+	}
+	if err := a.CheckLibraries(names); err != nil {
+		return nil, err
 	}
+	return link(a.Bytecode, a.LinkReferences, libraries)
+}
+
+// LinkDeployedBytecode returns the runtime bytecode with addresses of the libraries filled in.
+func (a *Artifact) LinkDeployedBytecode(libraries map[string]common.Address) ([]byte, error) {
+	return link(a.DeployedBytecode, a.DeployedLinkReferences, libraries)
+}
 
-	return value.String(), nil
+func link(code []byte, references []LinkReference, libraries map[string]common.Address) ([]byte, error) {
+	linked := make([]byte, len(code))
+	copy(linked, code)
+	for _, reference := range references {
+		address, ok := libraries[reference.LibraryName()]
+		if !ok {
+			return nil, fmt.Errorf("%w %s", ErrLibraryMissing, reference.LibraryName())
+		}
+		if reference.Start < 0 || reference.Length != common.AddressLength || reference.Start+reference.Length > len(linked) {
+			return nil, fmt.Errorf("%w: link reference of %s at %d is out of the bytecode", ErrArtifactWrongFieldFormat, reference.LibraryName(), reference.Start)
+		}
+		copy(linked[reference.Start:], address.Bytes())
+	}
+	return linked, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
 }
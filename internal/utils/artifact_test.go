@@ -5,44 +5,15 @@ import (
 	"fmt"
 	"os"
 	"reflect"
+	"strconv"
+	"strings"
 	"testing"
-)
-
-func TestGetBytecode(t *testing.T) {
 
-	// Invalid JSON
-	_, err := GetBytecode("{\"some\":\"stuff\"}")
-	if !errors.Is(err, ErrArtifactFieldNotFound) {
-		t.Fatalf("Expected error %v", err)
-	}
-
-	// Invalid field data format
-	_, err = GetBytecode("{\"bytecode\":\"stuff\"}")
-	if !errors.Is(err, ErrArtifactWrongFieldFormat) {
-		t.Fatalf("Expected error %v", err)
-	}
-	_, err = GetBytecode("{\"bytecode\":\"00ffaa\"}")
-	if !errors.Is(err, ErrArtifactWrongFieldFormat) {
-		t.Fatalf("Expected error %v", err)
-	}
-
-	// Success
-	b, err := os.ReadFile("../provider/testdata/Token.json")
-	if err != nil {
-		t.Fatalf("Cannot read test data file: %v", err)
-		fmt.Print(err)
-	}
-	bytecode, err := GetBytecode(string(b))
-	if err != nil {
-		t.Fatalf("Unexpected error when reading bytecode: %v", err)
-		fmt.Print(err)
-	}
-	expected := []byte{0x60, 0x80, 0x60, 0x40, 0x52, 0x34}
-	if !reflect.DeepEqual(bytecode[:6], expected) {
-		t.Fatalf("Wrong bytecode, expected %v, got %v", expected, bytecode)
-		fmt.Print(err)
-	}
-}
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+)
 
 func TestGetConstrutorArgs(t *testing.T) {
 	// Success
@@ -62,3 +33,209 @@ func TestGetConstrutorArgs(t *testing.T) {
 		fmt.Print(err)
 	}
 }
+
+func TestGetConstrutorArgsWithoutConstructor(t *testing.T) {
+	args, err := GetConstructorArgTypes(`{"abi":[{"type":"function","name":"deposit","inputs":[]}]}`)
+	if err != nil {
+		t.Fatalf("Unexpected error when reading constructor args: %v", err)
+	}
+	if len(args) != 0 {
+		t.Fatalf("Expected no constructor args, got %v", args)
+	}
+
+	_, err = GetConstructorArgTypes(`{"bytecode":"0x00"}`)
+	if !errors.Is(err, ErrArtifactFieldNotFound) {
+		t.Fatalf("Expected error %v", err)
+	}
+}
+
+func TestDetectArtifactFormat(t *testing.T) {
+	for artifact, expected := range map[string]ArtifactFormat{
+		`{"_format": "hh-sol-artifact-1", "abi": [], "bytecode": "0x00"}`:                              ArtifactFormatHardhat,
+		`{"abi": [], "bytecode": "0x00"}`:                                                              ArtifactFormatHardhat,
+		`{"abi": [], "bytecode": {"object": "0x00"}, "deployedBytecode": {"object": ""}}`:              ArtifactFormatFoundry,
+		`{"contractName": "Token", "abi": [], "bytecode": "0x00", "schemaVersion": "3.4.16"}`:          ArtifactFormatTruffle,
+		`{"contracts": {"Token.sol:Token": {"abi": [], "bin": "00"}}, "version": "0.8.20"}`:            ArtifactFormatSolc,
+		`{"Token.vy": {"abi": [], "bytecode": "0x00", "bytecode_runtime": "0x"}, "version": "0.3.10"}`: ArtifactFormatVyper,
+		`{"abi": [], "bytecode": "0x00", "bytecode_runtime": "0x"}`:                                    ArtifactFormatVyper,
+	} {
+		format, err := DetectArtifactFormat(artifact)
+		assert.NoError(t, err, artifact)
+		assert.Equal(t, expected, format, artifact)
+	}
+
+	_, err := DetectArtifactFormat(`{"abi": []}`)
+	assert.ErrorIs(t, err, ErrArtifactUnknownFormat)
+	_, err = DetectArtifactFormat(`not json`)
+	assert.ErrorIs(t, err, ErrArtifactWrongFieldFormat)
+}
+
+func TestParseArtifactFormat(t *testing.T) {
+	format, err := ParseArtifactFormat("foundry")
+	assert.NoError(t, err)
+	assert.Equal(t, ArtifactFormatFoundry, format)
+
+	format, err = ParseArtifactFormat("")
+	assert.NoError(t, err)
+	assert.Equal(t, ArtifactFormat(""), format)
+
+	_, err = ParseArtifactFormat("brownie")
+	assert.ErrorIs(t, err, ErrArtifactUnknownFormat)
+}
+
+// placeholder is the solc 0.5+ placeholder of the `src/Lib.sol:Lib` library.
+var placeholder = "__$" + crypto.Keccak256Hash([]byte("src/Lib.sol:Lib")).Hex()[2:36] + "$__"
+
+func TestParseArtifact(t *testing.T) {
+	abiJson := `[{"type":"constructor","inputs":[{"name":"supply","type":"uint256"}]}]`
+	for _, test := range []struct {
+		name     string
+		artifact string
+		expected Artifact
+	}{
+		{
+			name:     "hardhat",
+			artifact: `{"_format": "hh-sol-artifact-1", "abi": ` + abiJson + `, "bytecode": "0x6080` + placeholder + `00", "deployedBytecode": "0x60", "linkReferences": {"src/Lib.sol": {"Lib": [{"start": 2, "length": 20}]}}, "deployedLinkReferences": {}}`,
+			expected: Artifact{
+				Format:           ArtifactFormatHardhat,
+				Bytecode:         hexutil.MustDecode("0x6080" + strings.Repeat("00", 20) + "00"),
+				DeployedBytecode: []byte{0x60},
+				LinkReferences:   []LinkReference{{"src/Lib.sol", "Lib", 2, 20}},
+			},
+		},
+		{
+			name:     "foundry",
+			artifact: `{"abi": ` + abiJson + `, "bytecode": {"object": "0x6080` + placeholder + `", "linkReferences": {"src/Lib.sol": {"Lib": [{"start": 2, "length": 20}]}}}, "deployedBytecode": {"object": "0x60", "linkReferences": {}, "immutableReferences": {"7": [{"start": 1, "length": 32}]}}}`,
+			expected: Artifact{
+				Format:              ArtifactFormatFoundry,
+				Bytecode:            hexutil.MustDecode("0x6080" + strings.Repeat("00", 20)),
+				DeployedBytecode:    []byte{0x60},
+				LinkReferences:      []LinkReference{{"src/Lib.sol", "Lib", 2, 20}},
+				ImmutableReferences: []CodeRange{{1, 32}},
+			},
+		},
+		{
+			name:     "truffle",
+			artifact: `{"contractName": "Token", "abi": ` + abiJson + `, "bytecode": "0x6080__Lib___________________________________60", "deployedBytecode": "0x60__Lib___________________________________", "schemaVersion": "3.4.16"}`,
+			expected: Artifact{
+				Format:                 ArtifactFormatTruffle,
+				Bytecode:               hexutil.MustDecode("0x6080" + strings.Repeat("00", 20) + "60"),
+				DeployedBytecode:       hexutil.MustDecode("0x60" + strings.Repeat("00", 20)),
+				LinkReferences:         []LinkReference{{"", "Lib", 2, 20}},
+				DeployedLinkReferences: []LinkReference{{"", "Lib", 1, 20}},
+			},
+		},
+		{
+			name: "solc",
+			artifact: `{"contracts": {
+				"src/IToken.sol:IToken": {"abi": [], "bin": "", "bin-runtime": ""},
+				"src/Lib.sol:Lib": {"abi": "[]", "bin": "", "bin-runtime": ""},
+				"src/Token.sol:Token": {"abi": ` + strconv.Quote(abiJson) + `, "bin": "6080` + placeholder + `", "bin-runtime": "60"}
+			}, "version": "0.8.20"}`,
+			expected: Artifact{
+				Format:           ArtifactFormatSolc,
+				Bytecode:         hexutil.MustDecode("0x6080" + strings.Repeat("00", 20)),
+				DeployedBytecode: []byte{0x60},
+				LinkReferences:   []LinkReference{{"src/Lib.sol", "Lib", 2, 20}},
+			},
+		},
+		{
+			name:     "vyper",
+			artifact: `{"src/Token.vy": {"abi": ` + abiJson + `, "bytecode": "0x6080", "bytecode_runtime": "0x60"}, "version": "0.3.10"}`,
+			expected: Artifact{
+				Format:           ArtifactFormatVyper,
+				Bytecode:         []byte{0x60, 0x80},
+				DeployedBytecode: []byte{0x60},
+			},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			artifact, err := ParseArtifact(test.artifact, "", "")
+			if !assert.NoError(t, err) {
+				return
+			}
+			args, err := artifact.ConstructorArgTypes()
+			assert.NoError(t, err)
+			assert.Equal(t, []string{"uint256"}, args)
+
+			assert.JSONEq(t, abiJson, artifact.Abi)
+			artifact.Abi = ""
+			assert.Equal(t, test.expected, *artifact)
+		})
+	}
+}
+
+func TestParseArtifactErrors(t *testing.T) {
+	_, err := ParseArtifact(`{"abi": [], "bytecode": {"object": "0x00"}}`, ArtifactFormatHardhat, "")
+	assert.ErrorContains(t, err, "invalid hardhat artifact")
+
+	_, err = ParseArtifact(`{"abi": [], "bytecode": "00ffaa"}`, ArtifactFormatHardhat, "")
+	assert.ErrorIs(t, err, ErrArtifactWrongFieldFormat)
+
+	_, err = ParseArtifact(`{"contracts": {"A.sol:A": {"abi": [], "bin": "00"}, "B.sol:B": {"abi": [], "bin": "00"}}}`, "", "")
+	assert.ErrorContains(t, err, "expected a single contract with bytecode, found 2 [A.sol:A B.sol:B]")
+
+	_, err = ParseArtifact(`{"contracts": {"A.sol:A": {"abi": [], "bin": "00`+placeholder+`"}}}`, "", "")
+	assert.ErrorContains(t, err, "unknown library of placeholder")
+
+	_, err = ParseArtifact(`{"bytecode": "0x00"}`, "", "")
+	assert.ErrorIs(t, err, ErrArtifactFieldNotFound)
+
+	_, err = ParseArtifact(`{"abi": [], "bytecode": "0x00"}`, "brownie", "")
+	assert.ErrorIs(t, err, ErrArtifactUnknownFormat)
+}
+
+func TestParseArtifactContract(t *testing.T) {
+	solc := `{"contracts": {
+		"src/IToken.sol:IToken": {"abi": [], "bin": "", "bin-runtime": ""},
+		"src/Token.sol:Token": {"abi": [], "bin": "6001", "bin-runtime": "01"},
+		"src/Vault.sol:Vault": {"abi": [], "bin": "6002", "bin-runtime": "02"}
+	}}`
+	artifact, err := ParseArtifact(solc, "", "src/Vault.sol:Vault")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0x60, 0x02}, artifact.Bytecode)
+
+	_, err = ParseArtifact(solc, "", "src/IToken.sol:IToken")
+	assert.ErrorIs(t, err, ErrArtifactFieldNotFound)
+	assert.ErrorContains(t, err, "contract src/IToken.sol:IToken with bytecode, found [src/Token.sol:Token src/Vault.sol:Vault]")
+
+	vyper := `{"src/Token.vy": {"abi": [], "bytecode": "0x6001", "bytecode_runtime": "0x01"}, "src/Vault.vy": {"abi": [], "bytecode": "0x6002", "bytecode_runtime": "0x02"}}`
+	artifact, err = ParseArtifact(vyper, "", "src/Token.vy")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0x60, 0x01}, artifact.Bytecode)
+
+	_, err = ParseArtifact(vyper, "", "")
+	assert.ErrorContains(t, err, "expected a single contract with bytecode, found 2 [src/Token.vy src/Vault.vy]")
+
+	_, err = ParseArtifact(`{"abi": [], "bytecode": "0x00"}`, "", "src/Token.sol:Token")
+	assert.ErrorContains(t, err, "hardhat artifact has a single contract")
+}
+
+func TestLinkBytecode(t *testing.T) {
+	artifact := Artifact{
+		Bytecode: hexutil.MustDecode("0x73" + strings.Repeat("00", 20) + "73" + strings.Repeat("00", 20)),
+		LinkReferences: []LinkReference{
+			{"src/Lib.sol", "Lib", 1, 20},
+			{"", "Math", 22, 20},
+		},
+	}
+	assert.Equal(t, []string{"Math", "src/Lib.sol:Lib"}, artifact.Libraries())
+
+	lib := common.HexToAddress("0x76116eb4BcE815c280c471620286e606D51Eea73")
+	math := common.HexToAddress("0x000000000000000000000000000000000000dEaD")
+	bytecode, err := artifact.LinkBytecode(map[string]common.Address{"src/Lib.sol:Lib": lib, "Math": math})
+	assert.NoError(t, err)
+	assert.Equal(t, append(append([]byte{0x73}, lib.Bytes()...), append([]byte{0x73}, math.Bytes()...)...), bytecode)
+	// Unlinked bytecode is kept
+	assert.Equal(t, make([]byte, 20), artifact.Bytecode[1:21])
+
+	_, err = artifact.LinkBytecode(map[string]common.Address{"Lib": lib, "Math": math})
+	assert.ErrorIs(t, err, ErrLibraryMissing)
+	assert.ErrorIs(t, err, ErrLibraryUnused)
+	assert.ErrorContains(t, err, "missing library src/Lib.sol:Lib")
+	assert.ErrorContains(t, err, "unused library Lib, referenced libraries are [Math src/Lib.sol:Lib]")
+
+	artifact.LinkReferences[1].Start = 40
+	_, err = artifact.LinkBytecode(map[string]common.Address{"src/Lib.sol:Lib": lib, "Math": math})
+	assert.ErrorIs(t, err, ErrArtifactWrongFieldFormat)
+}
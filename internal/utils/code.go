@@ -0,0 +1,63 @@
+package utils
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/tidwall/gjson"
+)
+
+// CodeRange is a range of bytes in the contract code.
+type CodeRange struct {
+	Start  int
+	Length int
+}
+
+// GetImmutableReferences returns ranges of the runtime code holding values of immutable variables, which
+// are set by the constructor. References are listed by Foundry and solc artifacts in the
+// `deployedBytecode.immutableReferences` field, artifacts without it are considered to have no immutables.
+func GetImmutableReferences(artifactJson string) []CodeRange {
+	var ranges []CodeRange
+	gjson.Get(artifactJson, "deployedBytecode.immutableReferences").ForEach(func(_, references gjson.Result) bool {
+		for _, reference := range references.Array() {
+			ranges = append(ranges, CodeRange{int(reference.Get("start").Int()), int(reference.Get("length").Int())})
+		}
+		return true
+	})
+	return ranges
+}
+
+// MetadataLength returns length of the CBOR encoded metadata appended by Solidity and Vyper compilers
+// to the code, including the 2 bytes of its length, or 0 if the code doesn't end with metadata.
+func MetadataLength(code []byte) int {
+	if len(code) < 2 {
+		return 0
+	}
+	length := int(code[len(code)-2])<<8 | int(code[len(code)-1])
+	start := len(code) - 2 - length
+	// Metadata is a CBOR map with up to a few entries
+	if length == 0 || start < 0 || code[start] < 0xa1 || code[start] > 0xa5 {
+		return 0
+	}
+	return length + 2
+}
+
+// MaskCode returns copy of the code with immutable values zeroed and metadata removed, so that
+// the code of the same contract compares equal regardless of constructor args and compiler metadata.
+func MaskCode(code []byte, immutables []CodeRange) []byte {
+	masked := make([]byte, len(code)-MetadataLength(code))
+	copy(masked, code)
+	for _, immutable := range immutables {
+		if immutable.Start < 0 {
+			continue
+		}
+		for i := immutable.Start; i < immutable.Start+immutable.Length && i < len(masked); i++ {
+			masked[i] = 0
+		}
+	}
+	return masked
+}
+
+// CodeHash returns keccak256 hash of the masked code.
+func CodeHash(code []byte, immutables []CodeRange) common.Hash {
+	return crypto.Keccak256Hash(MaskCode(code, immutables))
+}
@@ -0,0 +1,38 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/assert"
+)
+
+// metadata is CBOR encoded {"solc": 0x000814} followed by its length.
+const metadata = "a164736f6c6343000814000a"
+
+func TestMaskCode(t *testing.T) {
+	code := hexutil.MustDecode("0x6080604052" + "7f" + "1111111111111111111111111111111111111111111111111111111111111111" + "00" + metadata)
+	masked := MaskCode(code, []CodeRange{{6, 32}})
+	assert.Equal(t, "0x6080604052"+"7f"+"0000000000000000000000000000000000000000000000000000000000000000"+"00", hexutil.Encode(masked))
+
+	otherMetadata := hexutil.MustDecode("0x6080604052" + "7f" + "2222222222222222222222222222222222222222222222222222222222222222" + "00" + "a164736f6c6343000815000a")
+	assert.Equal(t, CodeHash(code, []CodeRange{{6, 32}}), CodeHash(otherMetadata, []CodeRange{{6, 32}}))
+	assert.NotEqual(t, CodeHash(code, nil), CodeHash(otherMetadata, nil))
+
+	// Code without metadata and out of range references are kept as is
+	code = hexutil.MustDecode("0x600160005260206000f3")
+	assert.Equal(t, code, MaskCode(code, []CodeRange{{20, 32}, {-1, 2}}))
+}
+
+func TestMetadataLength(t *testing.T) {
+	assert.Equal(t, 12, MetadataLength(hexutil.MustDecode("0x6080"+metadata)))
+	assert.Equal(t, 0, MetadataLength(hexutil.MustDecode("0x600160005260206000f3")))
+	assert.Equal(t, 0, MetadataLength(hexutil.MustDecode("0x00ff")))
+	assert.Equal(t, 0, MetadataLength(nil))
+}
+
+func TestGetImmutableReferences(t *testing.T) {
+	artifact := `{"deployedBytecode": {"object": "0x00", "immutableReferences": {"12": [{"start": 10, "length": 32}, {"start": 70, "length": 32}]}}}`
+	assert.Equal(t, []CodeRange{{10, 32}, {70, 32}}, GetImmutableReferences(artifact))
+	assert.Empty(t, GetImmutableReferences(`{"deployedBytecode": "0x00"}`))
+}
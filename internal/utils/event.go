@@ -0,0 +1,60 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// ErrEventNotFound is returned for logs of events which are not declared in the ABIs.
+var ErrEventNotFound = errors.New("event not found")
+
+// DecodeEvent decodes the log with the first event declared in one of the ABIs matching its topic,
+// returning event name and arguments formatted as strings. Indexed arguments of dynamic types
+// (e.g. `string`) are only available as hashes of their values.
+func DecodeEvent(log *types.Log, abis ...*abi.ABI) (string, map[string]string, error) {
+	if len(log.Topics) == 0 {
+		return "", nil, ErrEventNotFound
+	}
+
+	for _, contractAbi := range abis {
+		if contractAbi == nil {
+			continue
+		}
+		event, err := contractAbi.EventByID(log.Topics[0])
+		if err != nil || event.Anonymous {
+			continue
+		}
+
+		// Unnamed arguments are named by position, as values are decoded by name
+		inputs := make(abi.Arguments, len(event.Inputs))
+		var indexed abi.Arguments
+		for i, input := range event.Inputs {
+			if input.Name == "" {
+				input.Name = fmt.Sprintf("arg%d", i)
+			}
+			inputs[i] = input
+			if input.Indexed {
+				indexed = append(indexed, input)
+			}
+		}
+
+		values := map[string]interface{}{}
+		if err := inputs.UnpackIntoMap(values, log.Data); err != nil {
+			return "", nil, fmt.Errorf("cannot decode data of event %s: %w", event.Sig, err)
+		}
+		if err := abi.ParseTopicsIntoMap(values, indexed, log.Topics[1:]); err != nil {
+			return "", nil, fmt.Errorf("cannot decode topics of event %s: %w", event.Sig, err)
+		}
+
+		args := make(map[string]string, len(inputs))
+		for _, input := range inputs {
+			args[input.Name] = formatArgument(values[input.Name])
+		}
+		return event.Name, args, nil
+	}
+
+	return "", nil, ErrEventNotFound
+}
@@ -0,0 +1,53 @@
+package utils
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeEvent(t *testing.T) {
+	eventsAbi, err := abi.JSON(strings.NewReader(`[
+		{"type":"event","name":"PoolCreated","inputs":[{"name":"token","type":"address","indexed":true},{"name":"","type":"uint24","indexed":true},{"name":"pool","type":"address"},{"name":"label","type":"string"}]}
+	]`))
+	if err != nil {
+		t.Fatalf("Cannot parse ABI: %v", err)
+	}
+	event := eventsAbi.Events["PoolCreated"]
+
+	token := common.HexToAddress("0x76116eb4BcE815c280c471620286e606D51Eea73")
+	pool := common.HexToAddress("0x000000000000000000000000000000000000dEaD")
+	data, err := event.Inputs.NonIndexed().Pack(pool, "USDC pool")
+	if err != nil {
+		t.Fatalf("Cannot pack event data: %v", err)
+	}
+
+	log := &types.Log{
+		Topics: []common.Hash{event.ID, common.BytesToHash(token.Bytes()), common.BigToHash(big.NewInt(3000))},
+		Data:   data,
+	}
+	name, args, err := DecodeEvent(log, nil, &eventsAbi)
+	assert.NoError(t, err)
+	assert.Equal(t, "PoolCreated", name)
+	assert.Equal(t, map[string]string{
+		"token": token.Hex(),
+		"arg1":  "3000",
+		"pool":  pool.Hex(),
+		"label": "USDC pool",
+	}, args)
+
+	_, _, err = DecodeEvent(&types.Log{Topics: []common.Hash{crypto.Keccak256Hash([]byte("Unknown()"))}}, &eventsAbi)
+	assert.ErrorIs(t, err, ErrEventNotFound)
+
+	_, _, err = DecodeEvent(&types.Log{}, &eventsAbi)
+	assert.ErrorIs(t, err, ErrEventNotFound)
+
+	_, _, err = DecodeEvent(&types.Log{Topics: log.Topics, Data: []byte{1}}, &eventsAbi)
+	assert.ErrorContains(t, err, "cannot decode data of event")
+}
@@ -42,6 +42,48 @@ func WeiToEther(wei string) float64 {
 	return result
 }
 
+var ErrInvalidAmount = errors.New("invalid amount")
+
+// weiUnits are decimals of the supported ether units.
+var weiUnits = map[string]int64{
+	"wei":    0,
+	"kwei":   3,
+	"mwei":   6,
+	"gwei":   9,
+	"szabo":  12,
+	"finney": 15,
+	"ether":  18,
+}
+
+var amountRegex = regexp.MustCompile(`^\s*([0-9]+(?:\.[0-9]+)?)\s*([a-zA-Z]*)\s*$`)
+
+// ParseWei converts amount in wei (e.g. `1000`) or with a unit suffix (e.g. `1.5 gwei` or `2 ether`) to wei.
+func ParseWei(amount string) (*big.Int, error) {
+	matches := amountRegex.FindStringSubmatch(amount)
+	if matches == nil {
+		return nil, errors.Join(ErrInvalidAmount, fmt.Errorf("'%s' should be a number with optional unit (wei, gwei or ether)", amount))
+	}
+
+	unit := strings.ToLower(matches[2])
+	if unit == "" {
+		unit = "wei"
+	}
+	decimals, ok := weiUnits[unit]
+	if !ok {
+		return nil, errors.Join(ErrInvalidAmount, fmt.Errorf("unknown unit '%s' in '%s'", matches[2], amount))
+	}
+
+	value, ok := new(big.Rat).SetString(matches[1])
+	if !ok {
+		return nil, errors.Join(ErrInvalidAmount, fmt.Errorf("cannot parse '%s'", amount))
+	}
+	value.Mul(value, new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(decimals), nil)))
+	if !value.IsInt() {
+		return nil, errors.Join(ErrInvalidAmount, fmt.Errorf("'%s' is not a whole number of wei", amount))
+	}
+	return value.Num(), nil
+}
+
 func ParseArguments(ctx context.Context, argTypes []string, argValues basetypes.ListValue) ([]interface{}, diag.Diagnostics) {
 
 	tflog.Info(ctx, "Parsing arguments")
@@ -78,6 +120,30 @@ func ParseArguments(ctx context.Context, argTypes []string, argValues basetypes.
 	return args, diags
 }
 
+// FormatArguments formats decoded values of contract function arguments as strings accepted by ParseArguments.
+func FormatArguments(values []interface{}) []string {
+	args := make([]string, len(values))
+	for i, value := range values {
+		args[i] = formatArgument(value)
+	}
+	return args
+}
+
+func formatArgument(value interface{}) string {
+	rv := reflect.ValueOf(value)
+	if (rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array) && rv.Type().Elem().Kind() != reflect.Uint8 {
+		elements := make([]string, rv.Len())
+		for i := range elements {
+			elements[i] = formatArgument(rv.Index(i).Interface())
+		}
+		return strings.Join(elements, ",")
+	}
+	if s, ok := value.(string); ok {
+		return s
+	}
+	return formatValue(value)
+}
+
 func GenerateFakeABI(ctx context.Context, name string, argTypes []string) (abi.ABI, error) {
 
 	type FakeInput struct {
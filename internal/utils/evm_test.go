@@ -177,3 +177,60 @@ func TestParseArgument(t *testing.T) {
 		})
 	}
 }
+
+func TestParseWei(t *testing.T) {
+	var test_data = []struct {
+		amount string
+		wei    string
+		err    error
+	}{
+		{"0", "0", nil},
+		{"1000", "1000", nil},
+		{"2 gwei", "2000000000", nil},
+		{"1.5gwei", "1500000000", nil},
+		{"1 ether", "1000000000000000000", nil},
+		{"0.001 ETHER", "1000000000000000", nil},
+		{"1.5 wei", "", ErrInvalidAmount},
+		{"-1", "", ErrInvalidAmount},
+		{"1 bitcoin", "", ErrInvalidAmount},
+		{"0x10", "", ErrInvalidAmount},
+	}
+
+	for _, data := range test_data {
+		wei, err := ParseWei(data.amount)
+		assertError(t, err, data.err, func() {
+			assert.Equal(t, data.wei, wei.String(), data.amount)
+		})
+	}
+}
+
+func TestFormatArguments(t *testing.T) {
+	address := common.HexToAddress("0x76116eb4BcE815c280c471620286e606D51Eea73")
+	args := FormatArguments([]interface{}{
+		address,
+		big.NewInt(-5),
+		true,
+		"text",
+		[]byte{1, 2},
+		[2]byte{3, 4},
+		[]common.Address{address, {}},
+		[2]*big.Int{big.NewInt(1), big.NewInt(2)},
+	})
+	assert.Equal(t, []string{
+		"0x76116eb4BcE815c280c471620286e606D51Eea73",
+		"-5",
+		"true",
+		"text",
+		"0x0102",
+		"0x0304",
+		"0x76116eb4BcE815c280c471620286e606D51Eea73,0x0000000000000000000000000000000000000000",
+		"1,2",
+	}, args)
+
+	// Formatted arguments are parsed back to the same values
+	for i, argType := range []string{"address", "int8", "bool", "string", "bytes", "bytes2", "address[]", "uint256[2]"} {
+		value, err := parseArgument(context.Background(), argType, args[i])
+		assert.NoError(t, err, argType)
+		assert.Equal(t, args[i], formatArgument(value), argType)
+	}
+}
@@ -0,0 +1,114 @@
+package utils
+
+import (
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/tyler-smith/go-bip39"
+)
+
+const DerivationPathIndex = "{index}"
+
+var (
+	ErrInvalidMnemonic       = errors.New("invalid mnemonic")
+	ErrInvalidDerivationPath = errors.New("invalid derivation path")
+	ErrInvalidDerivedKey     = errors.New("invalid derived key")
+)
+
+// NewMnemonic generates random BIP-39 mnemonic with the given number of words (12, 15, 18, 21 or 24).
+func NewMnemonic(words int) (string, error) {
+	if words < 12 || words > 24 || words%3 != 0 {
+		return "", fmt.Errorf("unsupported mnemonic length %d, expected 12, 15, 18, 21 or 24 words", words)
+	}
+
+	// Each 3 words encode 32 bits of entropy
+	entropy, err := bip39.NewEntropy(words / 3 * 32)
+	if err != nil {
+		return "", err
+	}
+	return bip39.NewMnemonic(entropy)
+}
+
+// MnemonicToSeed validates BIP-39 mnemonic and converts it to the seed using optional passphrase.
+func MnemonicToSeed(mnemonic string, passphrase string) ([]byte, error) {
+	seed, err := bip39.NewSeedWithErrorChecking(mnemonic, passphrase)
+	if err != nil {
+		return nil, errors.Join(ErrInvalidMnemonic, err)
+	}
+	return seed, nil
+}
+
+// AccountDerivationPath substitutes account index in the derivation path template (e.g. `m/44'/60'/0'/0/{index}`).
+func AccountDerivationPath(template string, index int) (accounts.DerivationPath, error) {
+	if strings.Count(template, DerivationPathIndex) != 1 {
+		return nil, errors.Join(ErrInvalidDerivationPath, fmt.Errorf("'%s' should contain exactly one %s placeholder", template, DerivationPathIndex))
+	}
+
+	path, err := accounts.ParseDerivationPath(strings.Replace(template, DerivationPathIndex, strconv.Itoa(index), 1))
+	if err != nil {
+		return nil, errors.Join(ErrInvalidDerivationPath, err)
+	}
+	return path, nil
+}
+
+// DeriveKey derives private key from the seed according to BIP-32.
+func DeriveKey(seed []byte, path accounts.DerivationPath) (*ecdsa.PrivateKey, error) {
+	key, chainCode, err := hmacSplit([]byte("Bitcoin seed"), seed)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, index := range path {
+		var data []byte
+		if index >= 0x80000000 {
+			// Hardened child: 0x00 || ser256(k) || ser32(i)
+			data = append([]byte{0x00}, crypto.FromECDSA(key)...)
+		} else {
+			// Normal child: serP(point(k)) || ser32(i)
+			data = crypto.CompressPubkey(&key.PublicKey)
+		}
+		data = binary.BigEndian.AppendUint32(data, index)
+
+		var childKey *ecdsa.PrivateKey
+		childKey, chainCode, err = hmacSplit(chainCode, data)
+		if err != nil {
+			return nil, err
+		}
+
+		n := crypto.S256().Params().N
+		k := new(big.Int).Add(childKey.D, key.D)
+		k.Mod(k, n)
+		if k.Sign() == 0 {
+			return nil, ErrInvalidDerivedKey
+		}
+		key, err = crypto.ToECDSA(k.FillBytes(make([]byte, 32)))
+		if err != nil {
+			return nil, errors.Join(ErrInvalidDerivedKey, err)
+		}
+	}
+
+	return key, nil
+}
+
+// hmacSplit calculates HMAC-SHA512 and splits it into a private key and a chain code.
+func hmacSplit(key []byte, data []byte) (*ecdsa.PrivateKey, []byte, error) {
+	mac := hmac.New(sha512.New, key)
+	mac.Write(data)
+	sum := mac.Sum(nil)
+
+	// ToECDSA rejects values outside of the curve order
+	privateKey, err := crypto.ToECDSA(sum[:32])
+	if err != nil {
+		return nil, nil, errors.Join(ErrInvalidDerivedKey, err)
+	}
+	return privateKey, sum[32:], nil
+}
@@ -0,0 +1,109 @@
+package utils
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeriveKeyBip32Vectors(t *testing.T) {
+	// Test vector 1 from BIP-32
+	seed, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+
+	var test_data = []struct {
+		path string
+		pk   string
+	}{
+		{"m", "e8f32e723decf4051aefac8e2c93c9c5b214313817cdb01a1494b917c8436b35"},
+		{"m/0'", "edb2e14f9ee77d26dd93b4ecede8d16ed408ce149b6cd80b0715a2d911a0afea"},
+		{"m/0'/1", "3c6cb8d0f6a264c91ea8b5030fadaa8e538b020f0a387421a12de9319dc93368"},
+		{"m/0'/1/2'", "cbce0d719ecf7431d88e6a89fa1483e02e35092af60c042b1df2ff59fa424dca"},
+		{"m/0'/1/2'/2", "0f479245fb19a38a1954c5c7c0ebab2f9bdfd96a17563ef28a6a4b1a2a764ef4"},
+		{"m/0'/1/2'/2/1000000000", "471b76e389e528d6de6d816857e012c5455051cad6660850e58372a6c3e6e7c8"},
+	}
+
+	for _, data := range test_data {
+		var path accounts.DerivationPath
+		if data.path != "m" {
+			var err error
+			path, err = accounts.ParseDerivationPath(data.path)
+			if err != nil {
+				t.Fatalf("Cannot parse path '%v': %v", data.path, err)
+			}
+		}
+		key, err := DeriveKey(seed, path)
+		assertError(t, err, nil, func() {
+			assert.Equal(t, data.pk, hex.EncodeToString(crypto.FromECDSA(key)), data.path)
+		})
+	}
+}
+
+func TestMnemonicToSeedBip39Vectors(t *testing.T) {
+	// Test vectors from BIP-39 reference implementation, all using "TREZOR" passphrase
+	var test_data = []struct {
+		mnemonic string
+		seed     string
+	}{
+		{
+			"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about",
+			"c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04",
+		},
+		{
+			"legal winner thank year wave sausage worth useful legal winner thank yellow",
+			"2e8905819b8723fe2c1d161860e5ee1830318dbf49a83bd451cfb8440c28bd6fa457fe1296106559a3c80937a1c1069be3a3a5bd381ee6260e8d9739fce1f607",
+		},
+	}
+
+	for _, data := range test_data {
+		seed, err := MnemonicToSeed(data.mnemonic, "TREZOR")
+		assertError(t, err, nil, func() {
+			assert.Equal(t, data.seed, hex.EncodeToString(seed))
+		})
+	}
+
+	_, err := MnemonicToSeed("abandon abandon abandon", "")
+	assertError(t, err, ErrInvalidMnemonic, func() {})
+}
+
+func TestDeriveEthereumAccount(t *testing.T) {
+	seed, err := MnemonicToSeed("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about", "")
+	if err != nil {
+		t.Fatalf("Unexpected error '%v'", err)
+	}
+
+	path, err := AccountDerivationPath("m/44'/60'/0'/0/{index}", 0)
+	if err != nil {
+		t.Fatalf("Unexpected error '%v'", err)
+	}
+	key, err := DeriveKey(seed, path)
+	if err != nil {
+		t.Fatalf("Unexpected error '%v'", err)
+	}
+	address, err := PrivateKeyToAddressString(key)
+	assertError(t, err, nil, func() {
+		assert.Equal(t, "0x9858EfFD232B4033E47d90003D41EC34EcaEda94", address)
+	})
+
+	_, err = AccountDerivationPath("m/44'/60'/0'/0/0", 0)
+	assertError(t, err, ErrInvalidDerivationPath, func() {})
+	_, err = AccountDerivationPath("m/44'/60'/x'/0/{index}", 0)
+	assertError(t, err, ErrInvalidDerivationPath, func() {})
+}
+
+func TestNewMnemonic(t *testing.T) {
+	for _, words := range []int{12, 15, 18, 21, 24} {
+		mnemonic, err := NewMnemonic(words)
+		assertError(t, err, nil, func() {
+			assert.Equal(t, words, len(strings.Fields(mnemonic)))
+			_, err := MnemonicToSeed(mnemonic, "")
+			assert.NoError(t, err)
+		})
+	}
+
+	_, err := NewMnemonic(13)
+	assert.Error(t, err)
+}
@@ -0,0 +1,71 @@
+package utils
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/tidwall/gjson"
+)
+
+var (
+	ErrKeystoreInvalid       = errors.New("invalid keystore")
+	ErrKeystoreWrongPassword = errors.New("wrong keystore password")
+)
+
+// ReadKeystore returns keystore JSON, treating the value as inline content
+// when it looks like a JSON object and as a file path otherwise.
+func ReadKeystore(keystoreOrPath string) ([]byte, error) {
+	if strings.HasPrefix(strings.TrimSpace(keystoreOrPath), "{") {
+		return []byte(keystoreOrPath), nil
+	}
+
+	content, err := os.ReadFile(keystoreOrPath)
+	if err != nil {
+		return nil, errors.Join(ErrKeystoreInvalid, err)
+	}
+	return content, nil
+}
+
+// KeystoreAddress returns the address recorded in keystore JSON in the
+// checksummed `0x` format.
+func KeystoreAddress(keystoreJson []byte) (string, error) {
+	if !gjson.ValidBytes(keystoreJson) {
+		return "", errors.Join(ErrKeystoreInvalid, errors.New("content is not valid JSON"))
+	}
+
+	value := gjson.GetBytes(keystoreJson, "address")
+	if !value.Exists() || !common.IsHexAddress(value.String()) {
+		return "", errors.Join(ErrKeystoreInvalid, errors.New("address field is missing or malformed"))
+	}
+	return common.HexToAddress(value.String()).Hex(), nil
+}
+
+// DecryptKeystore decrypts V3 keystore given as content or file path.
+func DecryptKeystore(keystoreOrPath string, password string) (*ecdsa.PrivateKey, error) {
+	keystoreJson, err := ReadKeystore(keystoreOrPath)
+	if err != nil {
+		return nil, err
+	}
+
+	address, err := KeystoreAddress(keystoreJson)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := keystore.DecryptKey(keystoreJson, password)
+	if errors.Is(err, keystore.ErrDecrypt) {
+		return nil, errors.Join(ErrKeystoreWrongPassword, fmt.Errorf("cannot decrypt keystore %s", address))
+	}
+	if err != nil {
+		return nil, errors.Join(ErrKeystoreInvalid, fmt.Errorf("keystore %s: %w", address, err))
+	}
+	if key.Address.Hex() != address {
+		return nil, errors.Join(ErrKeystoreInvalid, fmt.Errorf("keystore %s contains key for %s", address, key.Address.Hex()))
+	}
+	return key.PrivateKey, nil
+}
@@ -0,0 +1,55 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecryptKeystore(t *testing.T) {
+	privateKey, err := crypto.HexToECDSA("75ad181fe502bfc2031ec1ee5c6d34c65171cc44bca49ddf59940b1766420969")
+	if err != nil {
+		t.Fatalf("Error converting string to private key: %v", err)
+	}
+	keyJson, err := keystore.EncryptKey(&keystore.Key{
+		Address:    crypto.PubkeyToAddress(privateKey.PublicKey),
+		PrivateKey: privateKey,
+	}, "secret", keystore.LightScryptN, keystore.LightScryptP)
+	if err != nil {
+		t.Fatalf("Cannot encrypt key: %v", err)
+	}
+
+	keyPath := filepath.Join(t.TempDir(), "keystore.json")
+	if err := os.WriteFile(keyPath, keyJson, 0600); err != nil {
+		t.Fatalf("Cannot write keystore file: %v", err)
+	}
+
+	address, err := KeystoreAddress(keyJson)
+	assertError(t, err, nil, func() {
+		assert.Equal(t, "0x76116eb4BcE815c280c471620286e606D51Eea73", address)
+	})
+
+	var test_data = []struct {
+		keystore string
+		password string
+		err      error
+	}{
+		{string(keyJson), "secret", nil},
+		{keyPath, "secret", nil},
+		{string(keyJson), "wrong", ErrKeystoreWrongPassword},
+		{"{\"address\":\"0x76116eb4BcE815c280c471620286e606D51Eea73\"}", "secret", ErrKeystoreInvalid},
+		{"{not json", "secret", ErrKeystoreInvalid},
+		{filepath.Join(t.TempDir(), "missing.json"), "secret", ErrKeystoreInvalid},
+	}
+
+	for _, data := range test_data {
+		key, err := DecryptKeystore(data.keystore, data.password)
+		assertError(t, err, data.err, func() {
+			assert.Equal(t, privateKey.D, key.D)
+		})
+	}
+}
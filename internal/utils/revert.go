@@ -0,0 +1,136 @@
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+var (
+	errorSelector = crypto.Keccak256([]byte("Error(string)"))[:4]
+	panicSelector = crypto.Keccak256([]byte("Panic(uint256)"))[:4]
+)
+
+// panicReasons explain codes of Solidity panics, see
+// https://docs.soliditylang.org/en/latest/control-structures.html#panic-via-assert-and-error-via-require
+var panicReasons = map[uint64]string{
+	0x00: "generic compiler inserted panic",
+	0x01: "assertion failed",
+	0x11: "arithmetic underflow or overflow outside of unchecked block",
+	0x12: "division or modulo by zero",
+	0x21: "conversion of too big or negative value into enum type",
+	0x22: "access to incorrectly encoded storage byte array",
+	0x31: "pop() on an empty array",
+	0x32: "array or bytesN index out of bounds",
+	0x41: "too much memory allocated or too large array created",
+	0x51: "call to zero-initialized variable of internal function type",
+}
+
+// RevertData extracts revert data from the node error, which is only available while the error is not
+// converted to text (e.g. by bind wrapping gas estimation errors).
+func RevertData(err error) ([]byte, bool) {
+	var dataErr rpc.DataError
+	if !errors.As(err, &dataErr) {
+		return nil, false
+	}
+
+	switch data := dataErr.ErrorData().(type) {
+	case string:
+		decoded, err := hexutil.Decode(data)
+		if err != nil {
+			return nil, false
+		}
+		return decoded, true
+	case []byte:
+		return data, true
+	default:
+		return nil, false
+	}
+}
+
+// DecodeRevert formats revert data as `Error(string)` reason, `Panic(uint256)` code with explanation or
+// a custom error declared in one of the ABIs.
+func DecodeRevert(data []byte, abis ...*abi.ABI) string {
+	if len(data) == 0 {
+		return "reverted without a reason"
+	}
+	if len(data) < 4 {
+		return fmt.Sprintf("reverted with malformed data %s", hexutil.Encode(data))
+	}
+
+	selector := data[:4]
+	switch {
+	case bytes.Equal(selector, errorSelector):
+		if reason, err := abi.UnpackRevert(data); err == nil {
+			return fmt.Sprintf("Error(%q)", reason)
+		}
+	case bytes.Equal(selector, panicSelector):
+		if len(data) == 36 {
+			code := new(big.Int).SetBytes(data[4:])
+			reason, ok := panicReasons[code.Uint64()]
+			if !ok || !code.IsUint64() {
+				reason = "unknown panic code"
+			}
+			return fmt.Sprintf("Panic(0x%x): %s", code, reason)
+		}
+	default:
+		for _, contractAbi := range abis {
+			if contractAbi == nil {
+				continue
+			}
+			for _, abiError := range contractAbi.Errors {
+				if bytes.Equal(selector, abiError.ID[:4]) {
+					return formatCustomError(abiError, data[4:])
+				}
+			}
+		}
+		return fmt.Sprintf("unknown custom error %s with data %s (supply the ABI declaring the error to decode it)", hexutil.Encode(selector), hexutil.Encode(data[4:]))
+	}
+
+	return fmt.Sprintf("reverted with malformed data %s", hexutil.Encode(data))
+}
+
+func formatCustomError(abiError abi.Error, data []byte) string {
+	values, err := abiError.Inputs.Unpack(data)
+	if err != nil {
+		return fmt.Sprintf("%s with malformed data %s", abiError.Sig, hexutil.Encode(data))
+	}
+
+	args := make([]string, len(values))
+	for i, value := range values {
+		name := abiError.Inputs[i].Name
+		if name == "" {
+			name = fmt.Sprintf("arg%d", i)
+		}
+		args[i] = fmt.Sprintf("%s=%v", name, formatValue(value))
+	}
+	return fmt.Sprintf("%s(%s)", abiError.Name, strings.Join(args, ", "))
+}
+
+func formatValue(value interface{}) string {
+	switch v := value.(type) {
+	case []byte:
+		return hexutil.Encode(v)
+	case string:
+		return fmt.Sprintf("%q", v)
+	case common.Address:
+		return v.Hex()
+	default:
+		// Fixed size bytes are decoded to byte arrays
+		if rv := reflect.ValueOf(value); rv.Kind() == reflect.Array && rv.Type().Elem().Kind() == reflect.Uint8 {
+			raw := make([]byte, rv.Len())
+			reflect.Copy(reflect.ValueOf(raw), rv)
+			return hexutil.Encode(raw)
+		}
+		return fmt.Sprintf("%v", v)
+	}
+}
@@ -0,0 +1,74 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/assert"
+)
+
+type testDataError struct {
+	data interface{}
+}
+
+func (e testDataError) Error() string          { return "execution reverted" }
+func (e testDataError) ErrorData() interface{} { return e.data }
+
+func TestDecodeRevert(t *testing.T) {
+	errorsAbi, err := abi.JSON(strings.NewReader(`[
+		{"type":"error","name":"InsufficientBalance","inputs":[{"name":"available","type":"uint256"},{"name":"required","type":"uint256"}]},
+		{"type":"error","name":"Unauthorized","inputs":[{"name":"","type":"address"},{"name":"role","type":"bytes32"}]}
+	]`))
+	if err != nil {
+		t.Fatalf("Cannot parse ABI: %v", err)
+	}
+
+	pack := func(signature string, types []string, values ...interface{}) []byte {
+		fakeAbi, err := GenerateFakeABI(context.Background(), signature, types)
+		if err != nil {
+			t.Fatalf("Cannot generate ABI: %v", err)
+		}
+		data, err := fakeAbi.Pack(signature, values...)
+		if err != nil {
+			t.Fatalf("Cannot pack %s: %v", signature, err)
+		}
+		return data
+	}
+
+	var test_data = []struct {
+		data   []byte
+		reason string
+	}{
+		{nil, "reverted without a reason"},
+		{pack("Error", []string{"string"}, "not owner"), `Error("not owner")`},
+		{pack("Panic", []string{"uint256"}, big.NewInt(0x12)), "Panic(0x12): division or modulo by zero"},
+		{pack("Panic", []string{"uint256"}, big.NewInt(0x99)), "Panic(0x99): unknown panic code"},
+		{pack("InsufficientBalance", []string{"uint256", "uint256"}, big.NewInt(1), big.NewInt(2)), "InsufficientBalance(available=1, required=2)"},
+		{
+			pack("Unauthorized", []string{"address", "bytes32"}, common.HexToAddress("0x76116eb4BcE815c280c471620286e606D51Eea73"), [32]byte{1}),
+			"Unauthorized(arg0=0x76116eb4BcE815c280c471620286e606D51Eea73, role=0x0100000000000000000000000000000000000000000000000000000000000000)",
+		},
+		{[]byte{1, 2, 3, 4, 5}, "unknown custom error 0x01020304 with data 0x05 (supply the ABI declaring the error to decode it)"},
+		{[]byte{1, 2}, "reverted with malformed data 0x0102"},
+	}
+
+	for _, data := range test_data {
+		assert.Equal(t, data.reason, DecodeRevert(data.data, &errorsAbi), hexutil.Encode(data.data))
+	}
+}
+
+func TestRevertData(t *testing.T) {
+	data, ok := RevertData(fmt.Errorf("wrapped: %w", testDataError{"0x0102"}))
+	assert.True(t, ok)
+	assert.Equal(t, []byte{1, 2}, data)
+
+	_, ok = RevertData(errors.New("execution reverted"))
+	assert.False(t, ok)
+}
@@ -1,21 +1,39 @@
 package utils
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
 	"regexp"
+	"strings"
 
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 )
 
-func ParseNodeError(signer string, err error, diags *diag.Diagnostics) {
+// ParseNodeError adds diagnostics explaining known node errors, e.g. insufficient signer balance
+// to pay for gas and the transaction value (in wei).
+func ParseNodeError(signer string, value *big.Int, err error, diags *diag.Diagnostics) {
+	valueDetails := ""
+	if value != nil && value.Sign() > 0 {
+		valueDetails = fmt.Sprintf(" including %v ETH value", WeiToEther(value.String()))
+	}
+
 	regexpInsufficientGas := regexp.MustCompile(`insufficient\sfunds\sfor\sgas\s\*\sprice\s\+\svalue:\sbalance\s(\d+),\stx\scost\s(\d+),\sovershot\s(\d+)`)
 	matches := regexpInsufficientGas.FindStringSubmatch(err.Error())
 	if matches != nil {
 		diags.AddError(
 			"Insufficient gas error",
 			fmt.Sprintf(
-				"Transaction cost %v ETH, have %v ETH\nFund '%s' at least %v ETH",
+				"Transaction cost %v ETH%s, have %v ETH\nFund '%s' at least %v ETH",
 				WeiToEther(matches[2]),
+				valueDetails,
 				WeiToEther(matches[1]),
 				signer,
 				WeiToEther(matches[3]),
@@ -23,4 +41,88 @@ func ParseNodeError(signer string, err error, diags *diag.Diagnostics) {
 		)
 		return
 	}
+
+	// Gas estimation reports required and available balance instead of the transaction cost
+	regexpInsufficientBalance := regexp.MustCompile(`insufficient\sfunds\sfor\sgas\s\*\sprice\s\+\svalue:\saddress\s0x[0-9a-fA-F]+\shave\s(\d+)\swant\s(\d+)`)
+	matches = regexpInsufficientBalance.FindStringSubmatch(err.Error())
+	if matches != nil {
+		have, _ := new(big.Int).SetString(matches[1], 10)
+		want, _ := new(big.Int).SetString(matches[2], 10)
+		diags.AddError(
+			"Insufficient funds error",
+			fmt.Sprintf(
+				"Transaction requires %v ETH%s, have %v ETH\nFund '%s' at least %v ETH",
+				WeiToEther(matches[2]),
+				valueDetails,
+				WeiToEther(matches[1]),
+				signer,
+				WeiToEther(new(big.Int).Sub(want, have).String()),
+			),
+		)
+		return
+	}
+
+	if strings.Contains(err.Error(), core.ErrInsufficientFundsForTransfer.Error()) {
+		diags.AddError(
+			"Insufficient funds error",
+			fmt.Sprintf("Balance of '%s' is not enough to send transaction%s\n%v", signer, valueDetails, err),
+		)
+		return
+	}
+}
+
+// retryableMessages are node errors caused by races in the transaction pool, which go away when the
+// transaction is prepared and sent again.
+var retryableMessages = []string{
+	txpool.ErrReplaceUnderpriced.Error(),
+	core.ErrNonceTooLow.Error(),
+	core.ErrNonceTooHigh.Error(),
+	"timeout",
+	"timed out",
+	"too many requests",
+	"rate limit",
+	"connection reset",
+	"connection refused",
+}
+
+// regexpHTTPStatus matches status of rpc.HTTPError which is only available as text
+// when the error is wrapped with %v (e.g. by bind on gas estimation).
+var regexpHTTPStatus = regexp.MustCompile(`(^|:\s)(429|5\d\d)\s[A-Za-z]`)
+
+// rpcCodeLimitExceeded is JSON-RPC error code returned by node providers on rate limiting.
+const rpcCodeLimitExceeded = -32005
+
+// IsRetryableError reports whether the node request failed because of a transient condition
+// (timeout, rate limiting, server error or a transaction pool race) and can be retried.
+// Other errors (e.g. reverts or insufficient funds) are fatal.
+func IsRetryableError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, os.ErrDeadlineExceeded) {
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	var httpErr rpc.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode == http.StatusTooManyRequests || httpErr.StatusCode >= http.StatusInternalServerError
+	}
+
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) && rpcErr.ErrorCode() == rpcCodeLimitExceeded {
+		return true
+	}
+
+	message := strings.ToLower(err.Error())
+	for _, retryable := range retryableMessages {
+		if strings.Contains(message, retryable) {
+			return true
+		}
+	}
+	return regexpHTTPStatus.MatchString(err.Error())
 }
@@ -0,0 +1,77 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/stretchr/testify/assert"
+)
+
+type testRpcError struct {
+	code int
+}
+
+func (e testRpcError) Error() string  { return "rpc error" }
+func (e testRpcError) ErrorCode() int { return e.code }
+
+func TestIsRetryableError(t *testing.T) {
+	var test_data = []struct {
+		err       error
+		retryable bool
+	}{
+		{nil, false},
+		{context.DeadlineExceeded, true},
+		{fmt.Errorf("failed to estimate gas needed: %w", context.DeadlineExceeded), true},
+		{rpc.HTTPError{StatusCode: 429, Status: "429 Too Many Requests"}, true},
+		{rpc.HTTPError{StatusCode: 502, Status: "502 Bad Gateway"}, true},
+		{rpc.HTTPError{StatusCode: 401, Status: "401 Unauthorized"}, false},
+		{fmt.Errorf("failed to estimate gas needed: %v", rpc.HTTPError{StatusCode: 503, Status: "503 Service Unavailable"}), true},
+		{testRpcError{-32005}, true},
+		{testRpcError{-32000}, false},
+		{txpool.ErrReplaceUnderpriced, true},
+		{core.ErrNonceTooLow, true},
+		{errors.New("nonce too low: address 0x01, tx: 1 state: 2"), true},
+		{errors.New("execution reverted"), false},
+		{errors.New("insufficient funds for gas * price + value"), false},
+	}
+
+	for _, data := range test_data {
+		assert.Equal(t, data.retryable, IsRetryableError(data.err), fmt.Sprintf("%v", data.err))
+	}
+}
+
+func TestParseNodeError(t *testing.T) {
+	signer := "0x76116eb4BcE815c280c471620286e606D51Eea73"
+	value := big.NewInt(params.Ether)
+
+	var diags diag.Diagnostics
+	ParseNodeError(signer, value, errors.New("insufficient funds for gas * price + value: balance 500000000000000000, tx cost 1500000000000000000, overshot 1000000000000000000"), &diags)
+	if assert.Len(t, diags, 1) {
+		assert.Equal(t, "Transaction cost 1.5 ETH including 1 ETH value, have 0.5 ETH\nFund '"+signer+"' at least 1 ETH", diags[0].Detail())
+	}
+
+	diags = nil
+	ParseNodeError(signer, value, fmt.Errorf("failed to estimate gas needed: %v", core.ErrInsufficientFundsForTransfer), &diags)
+	if assert.Len(t, diags, 1) {
+		assert.True(t, strings.Contains(diags[0].Detail(), "including 1 ETH value"), diags[0].Detail())
+	}
+
+	diags = nil
+	ParseNodeError(signer, value, errors.New("failed to estimate gas needed: insufficient funds for gas * price + value: address "+signer+" have 250000000000000000 want 1000000000000000000"), &diags)
+	if assert.Len(t, diags, 1) {
+		assert.Equal(t, "Transaction requires 1 ETH including 1 ETH value, have 0.25 ETH\nFund '"+signer+"' at least 0.75 ETH", diags[0].Detail())
+	}
+
+	diags = nil
+	ParseNodeError(signer, nil, errors.New("execution reverted"), &diags)
+	assert.Len(t, diags, 0)
+}
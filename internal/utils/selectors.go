@@ -0,0 +1,59 @@
+package utils
+
+import (
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// knownMethods are signatures of widely used standard methods, which are recognized by their selectors
+// when the contract ABI is not available.
+var knownMethods = []string{
+	// ERC-20
+	"transfer(address,uint256)",
+	"approve(address,uint256)",
+	"transferFrom(address,address,uint256)",
+	"mint(address,uint256)",
+	"burn(uint256)",
+	// ERC-721 and ERC-1155
+	"safeTransferFrom(address,address,uint256)",
+	"safeTransferFrom(address,address,uint256,bytes)",
+	"safeTransferFrom(address,address,uint256,uint256,bytes)",
+	"setApprovalForAll(address,bool)",
+	// Ownable and AccessControl
+	"transferOwnership(address)",
+	"renounceOwnership()",
+	"acceptOwnership()",
+	"grantRole(bytes32,address)",
+	"revokeRole(bytes32,address)",
+	"renounceRole(bytes32,address)",
+	// Pausable
+	"pause()",
+	"unpause()",
+	// Proxies
+	"upgradeTo(address)",
+	"upgradeToAndCall(address,bytes)",
+	"changeAdmin(address)",
+	"upgrade(address,address)",
+	"upgradeAndCall(address,address,bytes)",
+	"changeProxyAdmin(address,address)",
+	"initialize()",
+	// WETH
+	"deposit()",
+	"withdraw(uint256)",
+}
+
+var knownSelectors = func() map[[4]byte]string {
+	selectors := make(map[[4]byte]string, len(knownMethods))
+	for _, method := range knownMethods {
+		selectors[[4]byte(crypto.Keccak256([]byte(method))[:4])] = method
+	}
+	return selectors
+}()
+
+// MethodBySelector returns signature of the known standard method with the selector.
+func MethodBySelector(selector []byte) (string, bool) {
+	if len(selector) < 4 {
+		return "", false
+	}
+	method, ok := knownSelectors[[4]byte(selector[:4])]
+	return method, ok
+}
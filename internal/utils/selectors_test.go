@@ -0,0 +1,20 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMethodBySelector(t *testing.T) {
+	method, ok := MethodBySelector(hexutil.MustDecode("0xa9059cbb000000"))
+	assert.True(t, ok)
+	assert.Equal(t, "transfer(address,uint256)", method)
+
+	_, ok = MethodBySelector(hexutil.MustDecode("0x01020304"))
+	assert.False(t, ok)
+
+	_, ok = MethodBySelector([]byte{0xa9})
+	assert.False(t, ok)
+}
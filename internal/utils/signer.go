@@ -0,0 +1,153 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const (
+	RemoteSignMethodEth     = "eth_signTransaction"
+	RemoteSignMethodAccount = "account_signTransaction"
+)
+
+var ErrRemoteSigner = errors.New("remote signer error")
+
+// RemoteSigner signs transactions with a JSON-RPC signer (e.g. Clef or Web3Signer) holding the key.
+type RemoteSigner struct {
+	client  *rpc.Client
+	method  string
+	address common.Address
+}
+
+// TransactionArgs are transaction fields sent to the remote signer or to the node signing with an unlocked account.
+type TransactionArgs struct {
+	From                 common.Address  `json:"from"`
+	To                   *common.Address `json:"to,omitempty"`
+	Gas                  hexutil.Uint64  `json:"gas"`
+	GasPrice             *hexutil.Big    `json:"gasPrice,omitempty"`
+	MaxFeePerGas         *hexutil.Big    `json:"maxFeePerGas,omitempty"`
+	MaxPriorityFeePerGas *hexutil.Big    `json:"maxPriorityFeePerGas,omitempty"`
+	Value                *hexutil.Big    `json:"value"`
+	Nonce                hexutil.Uint64  `json:"nonce"`
+	Data                 hexutil.Bytes   `json:"data"`
+	ChainID              *hexutil.Big    `json:"chainId"`
+}
+
+func NewRemoteSigner(client *rpc.Client, method string, address common.Address) (*RemoteSigner, error) {
+	if method != RemoteSignMethodEth && method != RemoteSignMethodAccount {
+		return nil, errors.Join(ErrRemoteSigner, fmt.Errorf("unsupported signing method '%s'", method))
+	}
+	return &RemoteSigner{client, method, address}, nil
+}
+
+// NewTransactionArgs converts unsigned transaction to JSON-RPC transaction arguments.
+func NewTransactionArgs(from common.Address, tx *types.Transaction, chainID *big.Int) TransactionArgs {
+	args := TransactionArgs{
+		From:    from,
+		To:      tx.To(),
+		Gas:     hexutil.Uint64(tx.Gas()),
+		Value:   (*hexutil.Big)(tx.Value()),
+		Nonce:   hexutil.Uint64(tx.Nonce()),
+		Data:    tx.Data(),
+		ChainID: (*hexutil.Big)(chainID),
+	}
+	if tx.Type() == types.LegacyTxType {
+		args.GasPrice = (*hexutil.Big)(tx.GasPrice())
+	} else {
+		args.MaxFeePerGas = (*hexutil.Big)(tx.GasFeeCap())
+		args.MaxPriorityFeePerGas = (*hexutil.Big)(tx.GasTipCap())
+	}
+	return args
+}
+
+// SignTx sends unsigned transaction to the remote signer and verifies the signed transaction it returns.
+func (s *RemoteSigner) SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	var result json.RawMessage
+	if err := s.client.CallContext(ctx, &result, s.method, NewTransactionArgs(s.address, tx, chainID)); err != nil {
+		return nil, errors.Join(ErrRemoteSigner, fmt.Errorf("%s for %s: %w", s.method, s.address.Hex(), err))
+	}
+
+	raw, err := decodeSignResult(result)
+	if err != nil {
+		return nil, errors.Join(ErrRemoteSigner, err)
+	}
+
+	signedTx := new(types.Transaction)
+	if err := signedTx.UnmarshalBinary(raw); err != nil {
+		return nil, errors.Join(ErrRemoteSigner, fmt.Errorf("cannot decode signed transaction: %w", err))
+	}
+
+	sender, err := types.Sender(types.LatestSignerForChainID(chainID), signedTx)
+	if err != nil {
+		return nil, errors.Join(ErrRemoteSigner, fmt.Errorf("cannot recover signed transaction sender: %w", err))
+	}
+	if sender != s.address {
+		return nil, errors.Join(ErrRemoteSigner, fmt.Errorf("transaction signed by %s, expected %s", sender.Hex(), s.address.Hex()))
+	}
+	if field := mismatchedField(signedTx, tx, chainID); field != "" {
+		return nil, errors.Join(ErrRemoteSigner, fmt.Errorf("signed transaction does not match the requested one: %s differs", field))
+	}
+
+	return signedTx, nil
+}
+
+// decodeSignResult extracts raw transaction which is either returned directly (Web3Signer)
+// or as the `raw` field of the result object (Clef and Geth).
+func decodeSignResult(result json.RawMessage) ([]byte, error) {
+	var raw hexutil.Bytes
+	if err := json.Unmarshal(result, &raw); err == nil {
+		return raw, nil
+	}
+
+	var object struct {
+		Raw hexutil.Bytes `json:"raw"`
+	}
+	if err := json.Unmarshal(result, &object); err != nil || len(object.Raw) == 0 {
+		return nil, fmt.Errorf("unexpected signer response %s", string(result))
+	}
+	return object.Raw, nil
+}
+
+// mismatchedField returns the name of the first field of the signed transaction which differs from the
+// requested one, so the signer can't change the recipient, the value or the fees paid by the sender.
+func mismatchedField(signedTx *types.Transaction, tx *types.Transaction, chainID *big.Int) string {
+	switch {
+	case signedTx.ChainId().Cmp(chainID) != 0:
+		return "chain ID"
+	case signedTx.Nonce() != tx.Nonce():
+		return "nonce"
+	case !sameRecipient(signedTx.To(), tx.To()):
+		return "recipient"
+	case signedTx.Value().Cmp(tx.Value()) != 0:
+		return "value"
+	case !bytes.Equal(signedTx.Data(), tx.Data()):
+		return "data"
+	case signedTx.Gas() != tx.Gas():
+		return "gas limit"
+	case signedTx.Type() != tx.Type():
+		return "transaction type"
+	case tx.Type() == types.LegacyTxType && signedTx.GasPrice().Cmp(tx.GasPrice()) != 0:
+		return "gas price"
+	case signedTx.GasFeeCap().Cmp(tx.GasFeeCap()) != 0:
+		return "max fee per gas"
+	case signedTx.GasTipCap().Cmp(tx.GasTipCap()) != 0:
+		return "max priority fee per gas"
+	}
+	return ""
+}
+
+func sameRecipient(a *common.Address, b *common.Address) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
@@ -0,0 +1,113 @@
+package utils
+
+import (
+	"context"
+	"math/big"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+)
+
+type testSignerService struct {
+	t *testing.T
+	// tamper modifies the transaction before signing
+	tamper func(tx *types.DynamicFeeTx)
+}
+
+// SignTransaction signs transaction with the test key, ignoring requested sender.
+func (s *testSignerService) SignTransaction(args TransactionArgs) (hexutil.Bytes, error) {
+	privateKey, err := crypto.HexToECDSA("75ad181fe502bfc2031ec1ee5c6d34c65171cc44bca49ddf59940b1766420969")
+	if err != nil {
+		s.t.Fatalf("Error converting string to private key: %v", err)
+	}
+	unsignedTx := &types.DynamicFeeTx{
+		ChainID:   args.ChainID.ToInt(),
+		Nonce:     uint64(args.Nonce),
+		GasTipCap: args.MaxPriorityFeePerGas.ToInt(),
+		GasFeeCap: args.MaxFeePerGas.ToInt(),
+		Gas:       uint64(args.Gas),
+		To:        args.To,
+		Value:     args.Value.ToInt(),
+		Data:      args.Data,
+	}
+	if s.tamper != nil {
+		s.tamper(unsignedTx)
+	}
+	tx, err := types.SignNewTx(privateKey, types.LatestSignerForChainID(args.ChainID.ToInt()), unsignedTx)
+	if err != nil {
+		return nil, err
+	}
+	return tx.MarshalBinary()
+}
+
+func TestRemoteSignerSignTx(t *testing.T) {
+	service := &testSignerService{t: t}
+	server := rpc.NewServer()
+	if err := server.RegisterName("eth", service); err != nil {
+		t.Fatalf("Cannot register signer service: %v", err)
+	}
+	httpServer := httptest.NewServer(server)
+	defer httpServer.Close()
+
+	client, err := rpc.DialContext(context.TODO(), httpServer.URL)
+	if err != nil {
+		t.Fatalf("Cannot dial signer: %v", err)
+	}
+
+	to := common.HexToAddress("0x000000000000000000000000000000000000dead")
+	tx := types.NewTx(&types.DynamicFeeTx{
+		Nonce:     7,
+		GasTipCap: big.NewInt(1),
+		GasFeeCap: big.NewInt(2),
+		Gas:       21000,
+		To:        &to,
+		Value:     big.NewInt(3),
+	})
+
+	var test_data = []struct {
+		address common.Address
+		err     error
+	}{
+		{common.HexToAddress("0x76116eb4BcE815c280c471620286e606D51Eea73"), nil},
+		{to, ErrRemoteSigner},
+	}
+
+	for _, data := range test_data {
+		signer, err := NewRemoteSigner(client, RemoteSignMethodEth, data.address)
+		if err != nil {
+			t.Fatalf("Unexpected error '%v'", err)
+		}
+		signedTx, err := signer.SignTx(context.TODO(), tx, big.NewInt(1337))
+		assertError(t, err, data.err, func() {
+			assert.Equal(t, uint64(7), signedTx.Nonce())
+			assert.Equal(t, to, *signedTx.To())
+		})
+	}
+
+	// Signed transaction differing from the requested one is rejected
+	signer, err := NewRemoteSigner(client, RemoteSignMethodEth, common.HexToAddress("0x76116eb4BcE815c280c471620286e606D51Eea73"))
+	if err != nil {
+		t.Fatalf("Unexpected error '%v'", err)
+	}
+	for field, tamper := range map[string]func(tx *types.DynamicFeeTx){
+		"gas limit":                func(tx *types.DynamicFeeTx) { tx.Gas = 1000000 },
+		"max fee per gas":          func(tx *types.DynamicFeeTx) { tx.GasFeeCap = big.NewInt(1000) },
+		"max priority fee per gas": func(tx *types.DynamicFeeTx) { tx.GasTipCap = big.NewInt(2) },
+		"value":                    func(tx *types.DynamicFeeTx) { tx.Value = big.NewInt(4) },
+	} {
+		service.tamper = tamper
+		_, err := signer.SignTx(context.TODO(), tx, big.NewInt(1337))
+		assert.ErrorIs(t, err, ErrRemoteSigner, field)
+		assert.ErrorContains(t, err, field+" differs")
+	}
+	service.tamper = nil
+
+	_, err = NewRemoteSigner(client, "eth_sign", to)
+	assertError(t, err, ErrRemoteSigner, func() {})
+}